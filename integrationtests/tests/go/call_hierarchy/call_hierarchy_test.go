@@ -0,0 +1,126 @@
+package call_hierarchy_test
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestIncomingCalls tests the GetIncomingCalls tool with Go symbols
+func TestIncomingCalls(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		symbolName   string
+		file         string
+		line         int
+		column       int
+		depth        int
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Function called from multiple files",
+			symbolName:   "HelperFunction",
+			depth:        1,
+			expectedText: "ConsumerFunction",
+			snapshotName: "helper-function",
+		},
+		{
+			name:         "Function called from main",
+			symbolName:   "FooBar",
+			depth:        2,
+			expectedText: "main",
+			snapshotName: "foobar-function",
+		},
+		{
+			name:         "Method by position",
+			file:         "types.go",
+			line:         14,
+			column:       24,
+			depth:        1,
+			expectedText: "s.Method()",
+			snapshotName: "struct-method-position",
+		},
+		{
+			name:         "Symbol not found",
+			symbolName:   "NotFound",
+			depth:        1,
+			expectedText: "NotFound not found",
+			snapshotName: "not-found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := ""
+			if tc.file != "" {
+				filePath = filepath.Join(suite.WorkspaceDir, tc.file)
+			}
+
+			result, err := tools.GetIncomingCalls(ctx, suite.Client, tc.symbolName, filePath, tc.line, tc.column, tc.depth)
+			if err != nil {
+				t.Fatalf("Failed to get incoming calls: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Incoming calls do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "incoming_calls", tc.snapshotName, result)
+		})
+	}
+}
+
+// TestOutgoingCalls tests the GetOutgoingCalls tool with Go symbols
+func TestOutgoingCalls(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		symbolName   string
+		depth        int
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Function calling other workspace functions",
+			symbolName:   "ConsumerFunction",
+			depth:        1,
+			expectedText: "HelperFunction",
+			snapshotName: "consumer-function",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tools.GetOutgoingCalls(ctx, suite.Client, tc.symbolName, "", 0, 0, tc.depth)
+			if err != nil {
+				t.Fatalf("Failed to get outgoing calls: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Outgoing calls do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			// Calls into the standard library point at the local Go installation
+			result = strings.ReplaceAll(result, runtime.GOROOT(), "/GOROOT")
+
+			common.SnapshotTest(t, "go", "outgoing_calls", tc.snapshotName, result)
+		})
+	}
+}
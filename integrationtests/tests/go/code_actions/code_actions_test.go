@@ -0,0 +1,179 @@
+package code_actions_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+const actionsContent = `package main
+
+func UseStrings() string {
+	return strings.ToUpper("hello")
+}
+
+func MakeStruct() TestStruct {
+	return TestStruct{}
+}
+`
+
+// TestGetCodeActions tests listing code actions for ranges and diagnostics
+func TestGetCodeActions(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("actions.go", actionsContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "actions.go")
+
+	tests := []struct {
+		name         string
+		actionRange  tools.CodeActionRange
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Quick fix for missing import",
+			actionRange:  tools.CodeActionRange{FilePath: filePath, StartLine: 4, EndLine: 4, Kinds: []string{"quickfix"}},
+			expectedText: `Add import:  "strings" (quickfix)`,
+			snapshotName: "missing-import",
+		},
+		{
+			name:         "Quick fix for a specific diagnostic",
+			actionRange:  tools.CodeActionRange{FilePath: filePath, StartLine: 1, EndLine: 9, Diagnostic: "undefined: strings", Kinds: []string{"quickfix"}},
+			expectedText: "Fixes: L4:C9: undefined: strings",
+			snapshotName: "diagnostic",
+		},
+		{
+			name:         "Refactoring",
+			actionRange:  tools.CodeActionRange{FilePath: filePath, StartLine: 8, EndLine: 8, Kinds: []string{"refactor.rewrite"}},
+			expectedText: "Fill TestStruct",
+			snapshotName: "fill-struct",
+		},
+		{
+			name:         "No actions",
+			actionRange:  tools.CodeActionRange{FilePath: filePath, StartLine: 8, EndLine: 8, Kinds: []string{"quickfix"}},
+			expectedText: "No code actions available",
+			snapshotName: "none",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tools.GetCodeActions(ctx, suite.Client, tc.actionRange)
+			if err != nil {
+				t.Fatalf("Failed to get code actions: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Code actions do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "code_actions", tc.snapshotName, result)
+		})
+	}
+}
+
+// TestGetCodeActionsUnknownDiagnostic tests that a diagnostic filter with no match is an error
+func TestGetCodeActionsUnknownDiagnostic(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("actions.go", actionsContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "actions.go")
+
+	_, err := tools.GetCodeActions(ctx, suite.Client, tools.CodeActionRange{FilePath: filePath, StartLine: 1, EndLine: 9, Diagnostic: "no such diagnostic"})
+	if err == nil || !strings.Contains(err.Error(), "no diagnostic matching") {
+		t.Errorf("Expected no diagnostic matching error, got: %v", err)
+	}
+}
+
+// TestApplyCodeAction tests applying code actions and verifies the file content afterwards
+func TestApplyCodeAction(t *testing.T) {
+	tests := []struct {
+		name            string
+		startLine       int
+		kinds           []string
+		index           int
+		expectedContent string
+	}{
+		{
+			name:            "Add missing import",
+			startLine:       4,
+			kinds:           []string{"quickfix"},
+			index:           1,
+			expectedContent: `import "strings"`,
+		},
+		{
+			name:            "Fill struct",
+			startLine:       8,
+			kinds:           []string{"refactor.rewrite"},
+			index:           1,
+			expectedContent: `Name: "",`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := internal.GetTestSuite(t)
+
+			ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+			defer cancel()
+
+			if err := suite.WriteFile("actions.go", actionsContent); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			filePath := filepath.Join(suite.WorkspaceDir, "actions.go")
+
+			actionRange := tools.CodeActionRange{FilePath: filePath, StartLine: tc.startLine, EndLine: tc.startLine, Kinds: tc.kinds}
+			result, err := tools.ApplyCodeAction(ctx, suite.Client, actionRange, tc.index)
+			if err != nil {
+				t.Fatalf("Failed to apply code action: %v", err)
+			}
+
+			if !strings.Contains(result, "Applied code action") {
+				t.Errorf("Result does not report the applied action\nGot: %s", result)
+			}
+
+			content, err := suite.ReadFile("actions.go")
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if !strings.Contains(content, tc.expectedContent) {
+				t.Errorf("File does not contain expected content: %s\nGot: %s", tc.expectedContent, content)
+			}
+		})
+	}
+}
+
+// TestApplyCodeActionInvalidIndex tests that an out of range index is rejected
+func TestApplyCodeActionInvalidIndex(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("actions.go", actionsContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "actions.go")
+
+	actionRange := tools.CodeActionRange{FilePath: filePath, StartLine: 4, EndLine: 4, Kinds: []string{"quickfix"}}
+	_, err := tools.ApplyCodeAction(ctx, suite.Client, actionRange, 5)
+	if err == nil || !strings.Contains(err.Error(), "invalid code action index") {
+		t.Errorf("Expected invalid code action index error, got: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package completion_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestGetCompletions tests the GetCompletions tool with unsaved text
+func TestGetCompletions(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name           string
+		file           string
+		line           int
+		column         int
+		text           string
+		limit          int
+		expectedText   string
+		unexpectedText string
+		snapshotName   string
+	}{
+		{
+			name:         "Struct members",
+			file:         "types.go",
+			line:         32,
+			column:       56,
+			text:         "\n\ts.",
+			limit:        20,
+			expectedText: "GetName (Method) func() string",
+			snapshotName: "struct-members",
+		},
+		{
+			name:           "Limit results",
+			file:           "types.go",
+			line:           32,
+			column:         56,
+			text:           "\n\ts.",
+			limit:          2,
+			expectedText:   "(showing first 2)",
+			unexpectedText: "3. ",
+			snapshotName:   "limit",
+		},
+		{
+			name:         "Package members with documentation",
+			file:         "types.go",
+			line:         32,
+			column:       56,
+			text:         "\n\tfmt.Sprintl",
+			limit:        20,
+			expectedText: "Sprintln (Function)",
+			snapshotName: "package-members",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			before, err := suite.ReadFile(tc.file)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+
+			result, err := tools.GetCompletions(ctx, suite.Client, filePath, tc.line, tc.column, tc.text, tc.limit)
+			if err != nil {
+				t.Fatalf("Failed to get completions: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Completions do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+			if tc.unexpectedText != "" && strings.Contains(result, tc.unexpectedText) {
+				t.Errorf("Completions contain unexpected text: %s\nGot: %s", tc.unexpectedText, result)
+			}
+
+			after, err := suite.ReadFile(tc.file)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if before != after {
+				t.Errorf("Unsaved text should not be written to disk\nGot:\n%s", after)
+			}
+
+			common.SnapshotTest(t, "go", "completion", tc.snapshotName, result)
+		})
+	}
+}
+
+// TestGetCompletionsInvalidPosition tests that positions outside the file are rejected
+func TestGetCompletionsInvalidPosition(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	filePath := filepath.Join(suite.WorkspaceDir, "types.go")
+	_, err := tools.GetCompletions(ctx, suite.Client, filePath, 500, 1, "s.", 20)
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("Expected out of range error, got: %v", err)
+	}
+}
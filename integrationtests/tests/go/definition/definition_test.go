@@ -2,6 +2,7 @@ package definition_test
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -98,3 +99,70 @@ func TestReadDefinition(t *testing.T) {
 		})
 	}
 }
+
+// TestReadDefinitionAtPosition tests the ReadDefinitionAtPosition tool with symbols
+// identified by their position in a file
+func TestReadDefinitionAtPosition(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		file         string
+		line         int
+		column       int
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Function call",
+			file:         "consumer.go",
+			line:         7,
+			column:       13,
+			expectedText: "func HelperFunction() string",
+			snapshotName: "position-function",
+		},
+		{
+			name:         "Local variable",
+			file:         "consumer.go",
+			line:         8,
+			column:       14,
+			expectedText: "message := HelperFunction()",
+			snapshotName: "position-local-variable",
+		},
+		{
+			name:         "Struct field",
+			file:         "consumer.go",
+			line:         12,
+			column:       3,
+			expectedText: "ID        int",
+			snapshotName: "position-struct-field",
+		},
+		{
+			name:         "Method call",
+			file:         "consumer.go",
+			line:         19,
+			column:       16,
+			expectedText: "func (s *SharedStruct) Method() string",
+			snapshotName: "position-method",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			result, err := tools.ReadDefinitionAtPosition(ctx, suite.Client, filePath, tc.line, tc.column)
+			if err != nil {
+				t.Fatalf("Failed to read definition: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Definition does not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "definition", tc.snapshotName, result)
+		})
+	}
+}
@@ -25,7 +25,7 @@ func TestDiagnostics(t *testing.T) {
 		defer cancel()
 
 		filePath := filepath.Join(suite.WorkspaceDir, "clean.go")
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -50,7 +50,7 @@ func TestDiagnostics(t *testing.T) {
 		defer cancel()
 
 		filePath := filepath.Join(suite.WorkspaceDir, "main.go")
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -94,11 +94,8 @@ func TestDiagnostics(t *testing.T) {
 			t.Fatalf("Failed to open consumer.go: %v", err)
 		}
 
-		// Wait for files to be processed
-		time.Sleep(2 * time.Second)
-
 		// Get initial diagnostics for consumer.go
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -147,9 +144,6 @@ func HelperFunction(value int) string {
 			t.Fatalf("Failed to send DidChangeWatchedFiles: %v", err)
 		}
 
-		// Wait for LSP to process the change
-		time.Sleep(3 * time.Second)
-
 		// Force reopen the consumer file to ensure LSP reevaluates it
 		err = suite.Client.CloseFile(ctx, consumerPath)
 		if err != nil {
@@ -161,11 +155,8 @@ func HelperFunction(value int) string {
 			t.Fatalf("Failed to reopen consumer.go: %v", err)
 		}
 
-		// Wait for diagnostics to be generated
-		time.Sleep(3 * time.Second)
-
 		// Check diagnostics again on consumer file - should now have an error
-		result, err = tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true)
+		result, err = tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed after dependency change: %v", err)
 		}
@@ -182,4 +173,67 @@ func HelperFunction(value int) string {
 
 		common.SnapshotTest(t, "go", "diagnostics", "dependency", result)
 	})
+
+	// Test that related locations are shown with their source line
+	t.Run("RelatedInformation", func(t *testing.T) {
+		suite := internal.GetTestSuite(t)
+
+		ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+		defer cancel()
+
+		content := `package main
+
+func Redeclared() {}
+
+func Redeclared() {}
+`
+		if err := suite.WriteFile("redeclared.go", content); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		filePath := filepath.Join(suite.WorkspaceDir, "redeclared.go")
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 0, false, false)
+		if err != nil {
+			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
+		}
+
+		if !strings.Contains(result, "Related: "+filePath+":L3:C6: other declaration of Redeclared") {
+			t.Errorf("Expected the other declaration as related location but got: %s", result)
+		}
+		if !strings.Contains(result, "3|func Redeclared() {}") {
+			t.Errorf("Expected the source line of the related location but got: %s", result)
+		}
+
+		common.SnapshotTest(t, "go", "diagnostics", "related_information", result)
+	})
+
+	// Test listing the quick fixes for each diagnostic
+	t.Run("QuickFixes", func(t *testing.T) {
+		suite := internal.GetTestSuite(t)
+
+		ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+		defer cancel()
+
+		content := `package main
+
+func UseStrings() string {
+	return strings.ToUpper("hello")
+}
+`
+		if err := suite.WriteFile("fixes.go", content); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		filePath := filepath.Join(suite.WorkspaceDir, "fixes.go")
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 0, false, true)
+		if err != nil {
+			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
+		}
+
+		if !strings.Contains(result, `Fix: Add import:  "strings"`) {
+			t.Errorf("Expected the quick fix for the missing import but got: %s", result)
+		}
+
+		common.SnapshotTest(t, "go", "diagnostics", "quick_fixes", result)
+	})
 }
@@ -0,0 +1,89 @@
+package document_symbols_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestGetDocumentSymbols tests the GetDocumentSymbols tool with Go files
+func TestGetDocumentSymbols(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name           string
+		file           string
+		kinds          []string
+		maxDepth       int
+		expectedText   string
+		unexpectedText string
+		snapshotName   string
+	}{
+		{
+			name:         "Full outline",
+			file:         "types.go",
+			expectedText: "Field Constants []string",
+			snapshotName: "full",
+		},
+		{
+			name:           "Top level only",
+			file:           "types.go",
+			maxDepth:       1,
+			expectedText:   "Struct SharedStruct",
+			unexpectedText: "Field",
+			snapshotName:   "top-level",
+		},
+		{
+			name:           "Methods only",
+			file:           "types.go",
+			kinds:          []string{"method"},
+			expectedText:   "Method (*SharedStruct).Process",
+			unexpectedText: "Struct SharedStruct",
+			snapshotName:   "methods",
+		},
+		{
+			name:           "Nested kinds with filtered parent",
+			file:           "types.go",
+			kinds:          []string{"Field"},
+			expectedText:   "(in SharedStruct)",
+			unexpectedText: "Method",
+			snapshotName:   "fields",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			result, err := tools.GetDocumentSymbols(ctx, suite.Client, filePath, tc.kinds, tc.maxDepth)
+			if err != nil {
+				t.Fatalf("Failed to get document symbols: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Document symbols do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+			if tc.unexpectedText != "" && strings.Contains(result, tc.unexpectedText) {
+				t.Errorf("Document symbols contain unexpected text: %s\nGot: %s", tc.unexpectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "document_symbols", tc.snapshotName, result)
+		})
+	}
+
+	t.Run("Unknown kind", func(t *testing.T) {
+		filePath := filepath.Join(suite.WorkspaceDir, "types.go")
+		_, err := tools.GetDocumentSymbols(ctx, suite.Client, filePath, []string{"Gadget"}, 0)
+		if err == nil || !strings.Contains(err.Error(), "unknown symbol kind") {
+			t.Errorf("Expected an unknown symbol kind error, got: %v", err)
+		}
+	})
+}
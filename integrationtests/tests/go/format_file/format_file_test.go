@@ -0,0 +1,114 @@
+package format_file_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+const unformattedContent = `package main
+
+import "fmt"
+
+func Unformatted(  ) {
+fmt.Println("badly indented")
+    if true {
+  fmt.Println("nested")
+    }
+}
+`
+
+const formattedContent = `package main
+
+import "fmt"
+
+func Unformatted() {
+	fmt.Println("badly indented")
+	if true {
+		fmt.Println("nested")
+	}
+}
+`
+
+// TestFormatFile tests formatting a whole file
+func TestFormatFile(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("unformatted.go", unformattedContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "unformatted.go")
+
+	result, err := tools.FormatFile(ctx, suite.Client, filePath, 0, 0, 4, false)
+	if err != nil {
+		t.Fatalf("Failed to format file: %v", err)
+	}
+
+	if !strings.Contains(result, "lines removed") {
+		t.Errorf("Result does not summarize changed lines\nGot: %s", result)
+	}
+	common.SnapshotTest(t, "go", "format_file", "whole-file", result)
+
+	content, err := suite.ReadFile("unformatted.go")
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if content != formattedContent {
+		t.Errorf("File was not formatted as expected\nExpected:\n%s\nGot:\n%s", formattedContent, content)
+	}
+}
+
+// TestFormatFileAlreadyFormatted tests that a formatted file is left alone
+func TestFormatFileAlreadyFormatted(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	filePath := filepath.Join(suite.WorkspaceDir, "clean.go")
+	result, err := tools.FormatFile(ctx, suite.Client, filePath, 0, 0, 4, false)
+	if err != nil {
+		t.Fatalf("Failed to format file: %v", err)
+	}
+
+	if !strings.Contains(result, "Already formatted") {
+		t.Errorf("Expected file to already be formatted\nGot: %s", result)
+	}
+	common.SnapshotTest(t, "go", "format_file", "already-formatted", result)
+}
+
+// TestFormatFileRange tests that range formatting errors are reported, as gopls does not
+// support formatting a range
+func TestFormatFileRange(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("unformatted.go", unformattedContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "unformatted.go")
+
+	_, err := tools.FormatFile(ctx, suite.Client, filePath, 6, 6, 4, false)
+	if err == nil || !strings.Contains(err.Error(), "failed to format range") {
+		t.Errorf("Expected range formatting error, got: %v", err)
+	}
+
+	content, err := suite.ReadFile("unformatted.go")
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if content != unformattedContent {
+		t.Errorf("File should not have been changed\nGot:\n%s", content)
+	}
+}
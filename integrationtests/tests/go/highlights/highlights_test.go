@@ -0,0 +1,89 @@
+package highlights_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+const highlightsContent = `package main
+
+import "fmt"
+
+func Accumulate(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	fmt.Println(total)
+	total = total * 2
+	return total
+}
+`
+
+// TestGetHighlights tests the GetHighlights tool with reads and writes of a variable
+func TestGetHighlights(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("highlights.go", highlightsContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "highlights.go")
+
+	tests := []struct {
+		name         string
+		line         int
+		column       int
+		contextLines int
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Local variable",
+			line:         6,
+			column:       2,
+			contextLines: 0,
+			expectedText: "Writes: 3",
+			snapshotName: "local-variable",
+		},
+		{
+			name:         "Parameter with context",
+			line:         5,
+			column:       17,
+			contextLines: 1,
+			expectedText: "Reads: 1",
+			snapshotName: "parameter",
+		},
+		{
+			name:         "No symbol",
+			line:         2,
+			column:       1,
+			expectedText: "No highlights found",
+			snapshotName: "not-found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tools.GetHighlights(ctx, suite.Client, filePath, tc.line, tc.column, tc.contextLines)
+			if err != nil {
+				t.Fatalf("Failed to get highlights: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Highlights do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "highlights", tc.snapshotName, result)
+		})
+	}
+}
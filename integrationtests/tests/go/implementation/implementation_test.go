@@ -0,0 +1,80 @@
+package implementation_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestFindImplementations tests the FindImplementations tool with Go interfaces
+func TestFindImplementations(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		file         string
+		line         int
+		column       int
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Interface",
+			file:         "types.go",
+			line:         19,
+			column:       6,
+			expectedText: "type SharedStruct struct",
+			snapshotName: "interface",
+		},
+		{
+			name:         "Interface method",
+			file:         "types.go",
+			line:         21,
+			column:       2,
+			expectedText: "func (s *SharedStruct) GetName() string",
+			snapshotName: "interface-method",
+		},
+		{
+			name:         "Interface value",
+			file:         "consumer.go",
+			line:         24,
+			column:       20,
+			expectedText: "func (s *SharedStruct) GetName() string",
+			snapshotName: "interface-value",
+		},
+		{
+			name:         "No identifier",
+			file:         "clean.go",
+			line:         1,
+			column:       1,
+			expectedText: "failed to get implementations",
+			snapshotName: "not-found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			result, err := tools.FindImplementations(ctx, suite.Client, filePath, tc.line, tc.column)
+			if err != nil {
+				// Some positions are not valid for implementation requests
+				result = err.Error()
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Implementations do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "implementation", tc.snapshotName, result)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package inlay_hints_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+const hintsContent = `package main
+
+import "strings"
+
+func InferredTypes() {
+	counts := map[string]int{}
+	for key, value := range counts {
+		counts[key] = value + 1
+	}
+	joined := strings.Repeat("x", 3)
+	_ = joined
+	greeting := strings.Repeat("héllo 👋", 2)
+	_ = greeting
+}
+`
+
+// TestGetInlayHints tests the GetInlayHints tool with type and parameter hints
+func TestGetInlayHints(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("hints.go", hintsContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "hints.go")
+
+	tests := []struct {
+		name         string
+		startLine    int
+		endLine      int
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Whole file",
+			expectedText: "counts /*: map[string]int */ :=",
+			snapshotName: "whole-file",
+		},
+		{
+			name:         "Range variables",
+			startLine:    7,
+			endLine:      7,
+			expectedText: "key /*: string */, value /*: int */",
+			snapshotName: "range-variables",
+		},
+		{
+			name:         "Parameter names",
+			startLine:    10,
+			endLine:      10,
+			expectedText: "/* s: */ \"x\"",
+			snapshotName: "parameter-names",
+		},
+		{
+			name:         "Non-ASCII text before a hint",
+			startLine:    12,
+			endLine:      12,
+			expectedText: "greeting /*: string */ := strings.Repeat(/* s: */ \"héllo 👋\", /* count: */ 2)",
+			snapshotName: "non-ascii",
+		},
+		{
+			name:         "No hints",
+			startLine:    1,
+			endLine:      3,
+			expectedText: "No inlay hints found",
+			snapshotName: "no-hints",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tools.GetInlayHints(ctx, suite.Client, filePath, tc.startLine, tc.endLine)
+			if err != nil {
+				t.Fatalf("Failed to get inlay hints: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Inlay hints do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "inlay_hints", tc.snapshotName, result)
+		})
+	}
+}
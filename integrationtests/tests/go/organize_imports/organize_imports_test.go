@@ -0,0 +1,79 @@
+package organize_imports_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+const importsContent = `package main
+
+import (
+	"os"
+	"fmt"
+)
+
+func UseImports() string {
+	fmt.Println("hello")
+	return strings.ToUpper("hello")
+}
+`
+
+// TestOrganizeImports tests that missing imports are added and unused ones removed
+func TestOrganizeImports(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	if err := suite.WriteFile("imports.go", importsContent); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	filePath := filepath.Join(suite.WorkspaceDir, "imports.go")
+
+	result, err := tools.OrganizeImports(ctx, suite.Client, filePath)
+	if err != nil {
+		t.Fatalf("Failed to organize imports: %v", err)
+	}
+
+	if !strings.Contains(result, "Applied code action") {
+		t.Errorf("Result does not report the applied action\nGot: %s", result)
+	}
+	common.SnapshotTest(t, "go", "organize_imports", "missing-and-unused", result)
+
+	content, err := suite.ReadFile("imports.go")
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if !strings.Contains(content, "\"fmt\"\n\t\"strings\"\n)") {
+		t.Errorf("Imports were not organized\nGot:\n%s", content)
+	}
+	if strings.Contains(content, "\"os\"") {
+		t.Errorf("Unused import was not removed\nGot:\n%s", content)
+	}
+}
+
+// TestOrganizeImportsNoChanges tests a file whose imports are already organized
+func TestOrganizeImportsNoChanges(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	filePath := filepath.Join(suite.WorkspaceDir, "clean.go")
+	result, err := tools.OrganizeImports(ctx, suite.Client, filePath)
+	if err != nil {
+		t.Fatalf("Failed to organize imports: %v", err)
+	}
+
+	if strings.Contains(result, "Applied code action") {
+		t.Errorf("Expected no changes\nGot: %s", result)
+	}
+	common.SnapshotTest(t, "go", "organize_imports", "no-changes", result)
+}
@@ -2,6 +2,7 @@ package references_test
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -132,3 +133,72 @@ func countFilesInResult(result string) int {
 
 	return len(fileMap)
 }
+
+// TestFindReferencesAtPosition tests the FindReferencesAtPosition tool with symbols
+// that cannot be looked up by name
+func TestFindReferencesAtPosition(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name          string
+		file          string
+		line          int
+		column        int
+		expectedText  string
+		expectedFiles int
+		snapshotName  string
+	}{
+		{
+			name:          "Local variable",
+			file:          "consumer.go",
+			line:          7,
+			column:        2,
+			expectedText:  "fmt.Println(message)",
+			expectedFiles: 1,
+			snapshotName:  "position-local-variable",
+		},
+		{
+			name:          "Struct field",
+			file:          "types.go",
+			line:          8,
+			column:        2,
+			expectedText:  "return s.Name",
+			expectedFiles: 3, // types.go, consumer.go and another_consumer.go
+			snapshotName:  "position-struct-field",
+		},
+		{
+			name:          "No references",
+			file:          "clean.go",
+			line:          36,
+			column:        6,
+			expectedText:  "No references found at",
+			expectedFiles: 0,
+			snapshotName:  "position-not-found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			result, err := tools.FindReferencesAtPosition(ctx, suite.Client, filePath, tc.line, tc.column)
+			if err != nil {
+				t.Fatalf("Failed to find references: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("References do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			fileCount := countFilesInResult(result)
+			if fileCount < tc.expectedFiles {
+				t.Errorf("Expected references in at least %d files, but found in %d files",
+					tc.expectedFiles, fileCount)
+			}
+
+			common.SnapshotTest(t, "go", "references", tc.snapshotName, result)
+		})
+	}
+}
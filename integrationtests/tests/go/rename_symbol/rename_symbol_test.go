@@ -34,7 +34,7 @@ func TestRenameSymbol(t *testing.T) {
 
 		// Request to rename SharedConstant to UpdatedConstant at its definition
 		// The constant is defined at line 25, column 7 of types.go
-		result, err := tools.RenameSymbol(ctx, suite.Client, filePath, 25, 7, "UpdatedConstant")
+		result, err := tools.RenameSymbol(ctx, suite.Client, filePath, 25, 7, "UpdatedConstant", false, false)
 		if err != nil {
 			t.Fatalf("RenameSymbol failed: %v", err)
 		}
@@ -92,7 +92,7 @@ func TestRenameSymbol(t *testing.T) {
 
 		// Request to rename a symbol at a position where no symbol exists
 		// The clean.go file doesn't have content at this position
-		_, err = tools.RenameSymbol(ctx, suite.Client, filePath, 10, 10, "NewName")
+		_, err = tools.RenameSymbol(ctx, suite.Client, filePath, 10, 10, "NewName", false, false)
 
 		// Expect an error because there's no symbol at that position
 		if err == nil {
@@ -109,4 +109,105 @@ func TestRenameSymbol(t *testing.T) {
 
 		common.SnapshotTest(t, "go", "rename_symbol", "not_found", errorMessage)
 	})
+
+	// Test listing the diagnostics changed by a rename
+	t.Run("ShowDiagnostics", func(t *testing.T) {
+		suite := internal.GetTestSuite(t)
+
+		time.Sleep(2 * time.Second)
+
+		ctx, cancel := context.WithTimeout(suite.Context, 20*time.Second)
+		defer cancel()
+
+		filePath := filepath.Join(suite.WorkspaceDir, "types.go")
+		result, err := tools.RenameSymbol(ctx, suite.Client, filePath, 25, 7, "UpdatedConstant", false, true)
+		if err != nil {
+			t.Fatalf("RenameSymbol failed: %v", err)
+		}
+
+		if !strings.Contains(result, "No diagnostics were introduced or resolved.") {
+			t.Errorf("Expected a clean rename to leave the diagnostics unchanged but got: %s", result)
+		}
+
+		common.SnapshotTest(t, "go", "rename_symbol", "show_diagnostics", result)
+	})
+
+	// Test a dry run, which must report the changes without writing them
+	t.Run("DryRun", func(t *testing.T) {
+		suite := internal.GetTestSuite(t)
+
+		time.Sleep(2 * time.Second)
+
+		ctx, cancel := context.WithTimeout(suite.Context, 5*time.Second)
+		defer cancel()
+
+		filePath := filepath.Join(suite.WorkspaceDir, "types.go")
+		err := suite.Client.OpenFile(ctx, filePath)
+		if err != nil {
+			t.Fatalf("Failed to open types.go: %v", err)
+		}
+
+		typesBefore, err := suite.ReadFile("types.go")
+		if err != nil {
+			t.Fatalf("Failed to read types.go: %v", err)
+		}
+		consumerBefore, err := suite.ReadFile("consumer.go")
+		if err != nil {
+			t.Fatalf("Failed to read consumer.go: %v", err)
+		}
+
+		result, err := tools.RenameSymbol(ctx, suite.Client, filePath, 25, 7, "UpdatedConstant", true, false)
+		if err != nil {
+			t.Fatalf("RenameSymbol failed: %v", err)
+		}
+
+		if !strings.Contains(result, "Would rename 'SharedConstant'") {
+			t.Errorf("Expected the prepare rename range in the result but got: %s", result)
+		}
+		if !strings.Contains(result, "+const UpdatedConstant") {
+			t.Errorf("Expected a diff of the changes but got: %s", result)
+		}
+
+		common.SnapshotTest(t, "go", "rename_symbol", "dry_run", result)
+
+		typesAfter, err := suite.ReadFile("types.go")
+		if err != nil {
+			t.Fatalf("Failed to read types.go: %v", err)
+		}
+		consumerAfter, err := suite.ReadFile("consumer.go")
+		if err != nil {
+			t.Fatalf("Failed to read consumer.go: %v", err)
+		}
+		if typesAfter != typesBefore || consumerAfter != consumerBefore {
+			t.Errorf("Expected files to be unchanged after a dry run")
+		}
+	})
+
+	// Test a dry run at a position that cannot be renamed
+	t.Run("DryRunInvalidPosition", func(t *testing.T) {
+		suite := internal.GetTestSuite(t)
+
+		time.Sleep(2 * time.Second)
+
+		ctx, cancel := context.WithTimeout(suite.Context, 5*time.Second)
+		defer cancel()
+
+		filePath := filepath.Join(suite.WorkspaceDir, "types.go")
+		err := suite.Client.OpenFile(ctx, filePath)
+		if err != nil {
+			t.Fatalf("Failed to open types.go: %v", err)
+		}
+
+		// The package keyword on line 1 is not a symbol
+		_, err = tools.RenameSymbol(ctx, suite.Client, filePath, 1, 1, "NewName", true, false)
+		if err == nil {
+			t.Fatalf("Expected an error when renaming a keyword, but got success")
+		}
+
+		if !strings.Contains(err.Error(), "cannot rename symbol at L1:C1") {
+			t.Errorf("Expected error message about the invalid position but got: %s", err.Error())
+		}
+
+		common.SnapshotTest(t, "go", "rename_symbol", "dry_run_invalid_position", err.Error())
+	})
 }
@@ -0,0 +1,167 @@
+package replace_symbol_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestReplaceSymbol tests replacing the source of a symbol found by name
+func TestReplaceSymbol(t *testing.T) {
+	tests := []struct {
+		name           string
+		symbolName     string
+		filePath       string
+		newText        string
+		expectError    bool
+		expectContains []string
+		snapshotName   string
+	}{
+		{
+			name:       "Method",
+			symbolName: "SharedStruct.Method",
+			newText: `// Method is a method of SharedStruct
+func (s *SharedStruct) Method() string {
+	return "method: " + s.Name
+}`,
+			expectContains: []string{"}\n\n// Method is a method of SharedStruct\nfunc (s *SharedStruct) Method() string {\n\treturn \"method: \" + s.Name\n}\n\n// SharedInterface"},
+			snapshotName:   "method",
+		},
+		{
+			name:       "FunctionInFile",
+			symbolName: "FooBar",
+			filePath:   "main.go",
+			newText: `func FooBar() string {
+	return "Replaced"
+}`,
+			expectContains: []string{"// FooBar is a simple function for testing\nfunc FooBar() string {\n\treturn \"Replaced\"\n}"},
+			snapshotName:   "function_in_file",
+		},
+		{
+			name:       "UncleanFilePath",
+			symbolName: "HelperFunction",
+			filePath:   "./subdir/../helper.go",
+			newText: `func HelperFunction() string {
+	return "replaced"
+}`,
+			expectContains: []string{"// HelperFunction returns a string for testing\nfunc HelperFunction() string {\n\treturn \"replaced\"\n}"},
+			snapshotName:   "unclean_file_path",
+		},
+		{
+			name:         "NotFound",
+			symbolName:   "DoesNotExist",
+			newText:      "func DoesNotExist() {}",
+			expectError:  true,
+			snapshotName: "not_found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := internal.GetTestSuite(t)
+
+			ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+			defer cancel()
+
+			fileName := "types.go"
+			filePath := ""
+			if tc.filePath != "" {
+				fileName = filepath.Clean(tc.filePath)
+				// Not joined with filepath.Join, which would clean the path
+				filePath = suite.WorkspaceDir + string(filepath.Separator) + tc.filePath
+			}
+
+			result, err := tools.ReplaceSymbol(ctx, suite.Client, tc.symbolName, filePath, tc.newText)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("Expected an error but got: %s", result)
+				}
+				common.SnapshotTest(t, "go", "replace_symbol", tc.snapshotName, err.Error())
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplaceSymbol failed: %v", err)
+			}
+
+			common.SnapshotTest(t, "go", "replace_symbol", tc.snapshotName, result)
+
+			content, err := suite.ReadFile(fileName)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", fileName, err)
+			}
+			for _, expected := range tc.expectContains {
+				if !strings.Contains(content, expected) {
+					t.Errorf("Expected %s to contain %q but got:\n%s", fileName, expected, content)
+				}
+			}
+		})
+	}
+}
+
+// TestInsertAfterSymbol tests inserting code next to a symbol found by name
+func TestInsertAfterSymbol(t *testing.T) {
+	tests := []struct {
+		name           string
+		symbolName     string
+		fileName       string
+		before         bool
+		newText        string
+		expectContains string
+		snapshotName   string
+	}{
+		{
+			name:       "After",
+			symbolName: "HelperFunction",
+			fileName:   "helper.go",
+			newText: `// OtherHelper is inserted after HelperFunction
+func OtherHelper() string {
+	return "other"
+}`,
+			expectContains: "}\n\n// OtherHelper is inserted after HelperFunction\nfunc OtherHelper() string {\n\treturn \"other\"\n}\n",
+			snapshotName:   "after",
+		},
+		{
+			name:       "BeforeDocComment",
+			symbolName: "FooBar",
+			fileName:   "main.go",
+			before:     true,
+			newText: `// BarFoo is inserted before FooBar
+func BarFoo() string {
+	return "BarFoo"
+}`,
+			expectContains: "func BarFoo() string {\n\treturn \"BarFoo\"\n}\n\n// FooBar is a simple function for testing\nfunc FooBar() string {",
+			snapshotName:   "before_doc_comment",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := internal.GetTestSuite(t)
+
+			ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+			defer cancel()
+
+			filePath := filepath.Join(suite.WorkspaceDir, tc.fileName)
+			result, err := tools.InsertAfterSymbol(ctx, suite.Client, tc.symbolName, filePath, tc.newText, tc.before)
+			if err != nil {
+				t.Fatalf("InsertAfterSymbol failed: %v", err)
+			}
+
+			common.SnapshotTest(t, "go", "insert_after_symbol", tc.snapshotName, result)
+
+			content, err := suite.ReadFile(tc.fileName)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", tc.fileName, err)
+			}
+			if !strings.Contains(content, tc.expectContains) {
+				t.Errorf("Expected %s to contain %q but got:\n%s", tc.fileName, tc.expectContains, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package signature_help_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestGetSignatureHelp tests the GetSignatureHelp tool with existing and unsaved calls
+func TestGetSignatureHelp(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		file         string
+		line         int
+		column       int
+		text         string
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Existing call",
+			file:         "types.go",
+			line:         32,
+			column:       43,
+			expectedText: "2. a ...any (active)",
+			snapshotName: "existing-call",
+		},
+		{
+			name:         "Unsaved call",
+			file:         "types.go",
+			line:         32,
+			column:       56,
+			text:         "\n\tfmt.Fprintf(nil, ",
+			expectedText: "2. format string (active)",
+			snapshotName: "unsaved-call",
+		},
+		{
+			name:         "Outside a call",
+			file:         "types.go",
+			line:         33,
+			column:       2,
+			expectedText: "No signature help available",
+			snapshotName: "outside-call",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			before, err := suite.ReadFile(tc.file)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+
+			result, err := tools.GetSignatureHelp(ctx, suite.Client, filePath, tc.line, tc.column, tc.text)
+			if err != nil {
+				t.Fatalf("Failed to get signature help: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Signature help does not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			after, err := suite.ReadFile(tc.file)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if before != after {
+				t.Errorf("Unsaved text should not be written to disk\nGot:\n%s", after)
+			}
+
+			common.SnapshotTest(t, "go", "signature_help", tc.snapshotName, result)
+		})
+	}
+}
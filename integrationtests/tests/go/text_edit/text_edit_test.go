@@ -3,6 +3,7 @@ package text_edit_test
 import (
 	"context"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -172,7 +173,7 @@ func AnotherFunction() {
 			}
 
 			// Call the ApplyTextEdits tool with the non-URL file path
-			result, err := tools.ApplyTextEdits(ctx, suite.Client, testFilePath, tc.edits)
+			result, err := tools.ApplyTextEdits(ctx, suite.Client, testFilePath, tc.edits, false)
 			if err != nil {
 				t.Fatalf("Failed to apply text edits: %v", err)
 			}
@@ -320,7 +321,7 @@ func NewFunction() {
 			}
 
 			// Call the ApplyTextEdits tool
-			result, err := tools.ApplyTextEdits(ctx, suite.Client, testFilePath, tc.edits)
+			result, err := tools.ApplyTextEdits(ctx, suite.Client, testFilePath, tc.edits, false)
 			if err != nil {
 				t.Fatalf("Failed to apply text edits: %v", err)
 			}
@@ -347,3 +348,327 @@ func NewFunction() {
 		})
 	}
 }
+
+// TestApplyTextEditsWithExpectedContent tests that edits are rejected when the file no
+// longer has the expected text or hash
+func TestApplyTextEditsWithExpectedContent(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	testFileName := "expected_content_test.go"
+	testFilePath := filepath.Join(suite.WorkspaceDir, testFileName)
+
+	initialContent := `package main
+
+import "fmt"
+
+// Greet prints a greeting
+func Greet() {
+	fmt.Println("Hello")
+}
+`
+	// Read the hash from the definition tool, as a client would before editing
+	if err := suite.WriteFile(testFileName, initialContent); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	definition, err := tools.ReadDefinitionAtPosition(ctx, suite.Client, testFilePath, 6, 6)
+	if err != nil {
+		t.Fatalf("Failed to read definition: %v", err)
+	}
+	match := regexp.MustCompile(`File hash: ([0-9a-f]{64})`).FindStringSubmatch(definition)
+	if match == nil {
+		t.Fatalf("Expected the definition to include the file hash but got: %s", definition)
+	}
+	initialHash := match[1]
+
+	expected := func(text string) *string { return &text }
+
+	tests := []struct {
+		name      string
+		edits     []tools.TextEdit
+		expectErr string
+		contains  string
+	}{
+		{
+			name: "Matching expected text",
+			edits: []tools.TextEdit{
+				{
+					StartLine:    7,
+					EndLine:      7,
+					NewText:      `	fmt.Println("Hi")`,
+					ExpectedText: expected("\tfmt.Println(\"Hello\")\n"),
+				},
+			},
+			contains: "Successfully applied text edits",
+		},
+		{
+			name: "Mismatching expected text",
+			edits: []tools.TextEdit{
+				{
+					StartLine:    6,
+					EndLine:      7,
+					NewText:      "func Greet() {}",
+					ExpectedText: expected("func Greet() {\n\tfmt.Println(\"Goodbye\")"),
+				},
+			},
+			expectErr: "L6-L7: text does not match the expected text",
+		},
+		{
+			name: "Matching file hash",
+			edits: []tools.TextEdit{
+				{
+					StartLine:        5,
+					EndLine:          5,
+					NewText:          "// Greet prints a friendly greeting",
+					ExpectedFileHash: initialHash,
+				},
+			},
+			contains: "File hash: ",
+		},
+		{
+			name: "Mismatching file hash",
+			edits: []tools.TextEdit{
+				{
+					StartLine:        5,
+					EndLine:          5,
+					NewText:          "// Greet prints a friendly greeting",
+					ExpectedFileHash: strings.Repeat("0", 64),
+				},
+			},
+			expectErr: "L5-L5: file hash is " + initialHash,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := suite.WriteFile(testFileName, initialContent)
+			if err != nil {
+				t.Fatalf("Failed to reset test file: %v", err)
+			}
+
+			result, err := tools.ApplyTextEdits(ctx, suite.Client, testFilePath, tc.edits, false)
+			snapshotName := strings.ToLower(strings.ReplaceAll(tc.name, " ", "_"))
+
+			if tc.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected an error but got: %s", result)
+				}
+				errorMessage := err.Error()
+				if !strings.Contains(errorMessage, tc.expectErr) {
+					t.Errorf("Expected error containing %q but got: %s", tc.expectErr, errorMessage)
+				}
+
+				content, err := suite.ReadFile(testFileName)
+				if err != nil {
+					t.Fatalf("Failed to read test file: %v", err)
+				}
+				if content != initialContent {
+					t.Errorf("Expected the file to be unchanged after a rejected edit but got: %s", content)
+				}
+
+				common.SnapshotTest(t, "go", "text_edit", snapshotName, errorMessage)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to apply text edits: %v", err)
+			}
+			if !strings.Contains(result, tc.contains) {
+				t.Errorf("Expected result containing %q but got: %s", tc.contains, result)
+			}
+
+			common.SnapshotTest(t, "go", "text_edit", snapshotName, result)
+		})
+	}
+}
+
+// TestApplyTextEditsWithOldText tests edits anchored on text instead of line numbers
+func TestApplyTextEditsWithOldText(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	testFileName := "old_text_test.go"
+	testFilePath := filepath.Join(suite.WorkspaceDir, testFileName)
+
+	initialContent := `package main
+
+import "fmt"
+
+// First prints a message
+func First() {
+	fmt.Println("message")
+}
+
+// Second prints the same message
+func Second() {
+	fmt.Println("message")
+}
+`
+
+	text := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		content   string
+		edits     []tools.TextEdit
+		expectErr string
+		expected  string
+	}{
+		{
+			name:    "Replace unique text",
+			content: initialContent,
+			edits: []tools.TextEdit{
+				{
+					OldText: text("func First() {\n\tfmt.Println(\"message\")"),
+					NewText: "func First() {\n\tfmt.Println(\"first message\")",
+				},
+			},
+			expected: "func First() {\n\tfmt.Println(\"first message\")\n}",
+		},
+		{
+			name:    "Replace text within a line",
+			content: initialContent,
+			edits: []tools.TextEdit{
+				{
+					OldText: text("the same message"),
+					NewText: "another message",
+				},
+			},
+			expected: "// Second prints another message\n",
+		},
+		{
+			name:    "Remove lines including their newline",
+			content: initialContent,
+			edits: []tools.TextEdit{
+				{
+					OldText: text("// First prints a message\n"),
+					NewText: "",
+				},
+			},
+			expected: "\n\nfunc First() {",
+		},
+		{
+			name:    "Replace text in a file with CRLF line endings",
+			content: strings.ReplaceAll(initialContent, "\n", "\r\n"),
+			edits: []tools.TextEdit{
+				{
+					OldText: text("func Second() {\n\tfmt.Println(\"message\")"),
+					NewText: "func Second() {\n\tfmt.Println(\"second message\")",
+				},
+			},
+			expected: "func Second() {\r\n\tfmt.Println(\"second message\")\r\n}\r\n",
+		},
+		{
+			name:    "Ambiguous text",
+			content: initialContent,
+			edits: []tools.TextEdit{
+				{
+					OldText: text("fmt.Println(\"message\")"),
+					NewText: "fmt.Println(\"changed\")",
+				},
+			},
+			expectErr: "found 2 times at L7:C2, L12:C2",
+		},
+		{
+			name:    "Text not found",
+			content: initialContent,
+			edits: []tools.TextEdit{
+				{
+					OldText: text("func Third() {"),
+					NewText: "func Fourth() {",
+				},
+			},
+			expectErr: "oldText was not found in the file",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := suite.WriteFile(testFileName, tc.content)
+			if err != nil {
+				t.Fatalf("Failed to reset test file: %v", err)
+			}
+
+			result, err := tools.ApplyTextEdits(ctx, suite.Client, testFilePath, tc.edits, false)
+			snapshotName := "old_text_" + strings.ToLower(strings.ReplaceAll(tc.name, " ", "_"))
+
+			content, readErr := suite.ReadFile(testFileName)
+			if readErr != nil {
+				t.Fatalf("Failed to read test file: %v", readErr)
+			}
+
+			if tc.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected an error but got: %s", result)
+				}
+				errorMessage := err.Error()
+				if !strings.Contains(errorMessage, tc.expectErr) {
+					t.Errorf("Expected error containing %q but got: %s", tc.expectErr, errorMessage)
+				}
+				if content != tc.content {
+					t.Errorf("Expected the file to be unchanged after a rejected edit but got: %s", content)
+				}
+				common.SnapshotTest(t, "go", "text_edit", snapshotName, errorMessage)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to apply text edits: %v", err)
+			}
+			if !strings.Contains(content, tc.expected) {
+				t.Errorf("Expected file to contain %q but got:\n%s", tc.expected, content)
+			}
+
+			common.SnapshotTest(t, "go", "text_edit", snapshotName, result)
+		})
+	}
+}
+
+// TestApplyTextEditsWithDiagnostics tests listing the diagnostics introduced and resolved by edits
+func TestApplyTextEditsWithDiagnostics(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 30*time.Second)
+	defer cancel()
+
+	filePath := filepath.Join(suite.WorkspaceDir, "clean.go")
+	text := func(s string) *string { return &s }
+
+	// Introduce an error, then fix it again
+	result, err := tools.ApplyTextEdits(ctx, suite.Client, filePath, []tools.TextEdit{
+		{OldText: text("return t.Name"), NewText: "return t.Missing"},
+	}, true)
+	if err != nil {
+		t.Fatalf("Failed to apply text edits: %v", err)
+	}
+	if !strings.Contains(result, "Introduced: 1, resolved: 0") || !strings.Contains(result, "+ ERROR at L13:C11") {
+		t.Errorf("Expected the edit to introduce an error but got: %s", result)
+	}
+	common.SnapshotTest(t, "go", "text_edit", "diagnostics_introduced", result)
+
+	result, err = tools.ApplyTextEdits(ctx, suite.Client, filePath, []tools.TextEdit{
+		{OldText: text("return t.Missing"), NewText: "return t.Name"},
+	}, true)
+	if err != nil {
+		t.Fatalf("Failed to apply text edits: %v", err)
+	}
+	if !strings.Contains(result, "Introduced: 0, resolved: 1") {
+		t.Errorf("Expected the edit to resolve the error but got: %s", result)
+	}
+	common.SnapshotTest(t, "go", "text_edit", "diagnostics_resolved", result)
+
+	// Edits that don't change the diagnostics say so
+	result, err = tools.ApplyTextEdits(ctx, suite.Client, filePath, []tools.TextEdit{
+		{OldText: text("// TestType is a type alias"), NewText: "// TestType is a named string type"},
+	}, true)
+	if err != nil {
+		t.Fatalf("Failed to apply text edits: %v", err)
+	}
+	if !strings.Contains(result, "No diagnostics were introduced or resolved.") {
+		t.Errorf("Expected no diagnostics changes but got: %s", result)
+	}
+}
@@ -0,0 +1,71 @@
+package type_definition_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestReadTypeDefinition tests the ReadTypeDefinition tool with Go variables
+func TestReadTypeDefinition(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		file         string
+		line         int
+		column       int
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Struct variable",
+			file:         "consumer.go",
+			line:         11,
+			column:       2,
+			expectedText: "type SharedStruct struct",
+			snapshotName: "struct-variable",
+		},
+		{
+			name:         "Custom type variable",
+			file:         "consumer.go",
+			line:         27,
+			column:       6,
+			expectedText: "type SharedType int",
+			snapshotName: "type-variable",
+		},
+		{
+			name:         "Interface variable",
+			file:         "consumer.go",
+			line:         23,
+			column:       6,
+			expectedText: "type SharedInterface interface",
+			snapshotName: "interface-variable",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			result, err := tools.ReadTypeDefinition(ctx, suite.Client, filePath, tc.line, tc.column)
+			if err != nil {
+				t.Fatalf("Failed to get type definition: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Type definition does not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "type_definition", tc.snapshotName, result)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package type_hierarchy_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestTypeHierarchy tests the GetTypeHierarchy tool with Go interfaces and structs
+func TestTypeHierarchy(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		symbolName   string
+		file         string
+		line         int
+		column       int
+		direction    string
+		expectedText string
+		snapshotName string
+	}{
+		{
+			name:         "Interface implementations",
+			symbolName:   "SharedInterface",
+			direction:    "down",
+			expectedText: "SharedStruct",
+			snapshotName: "interface-subtypes",
+		},
+		{
+			name:         "Interfaces implemented by struct",
+			symbolName:   "SharedStruct",
+			direction:    "up",
+			expectedText: "SharedInterface",
+			snapshotName: "struct-supertypes",
+		},
+		{
+			name:         "Both directions by position",
+			file:         "types.go",
+			line:         19,
+			column:       6,
+			direction:    "both",
+			expectedText: "Subtypes:",
+			snapshotName: "interface-both-position",
+		},
+		{
+			name:         "Type not found",
+			symbolName:   "NotFound",
+			direction:    "both",
+			expectedText: "NotFound not found",
+			snapshotName: "not-found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := ""
+			if tc.file != "" {
+				filePath = filepath.Join(suite.WorkspaceDir, tc.file)
+			}
+
+			result, err := tools.GetTypeHierarchy(ctx, suite.Client, tc.symbolName, filePath, tc.line, tc.column, tc.direction, 2)
+			if err != nil {
+				t.Fatalf("Failed to get type hierarchy: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Type hierarchy does not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "type_hierarchy", tc.snapshotName, result)
+		})
+	}
+
+	t.Run("Invalid direction", func(t *testing.T) {
+		_, err := tools.GetTypeHierarchy(ctx, suite.Client, "SharedInterface", "", 0, 0, "sideways", 1)
+		if err == nil {
+			t.Errorf("Expected an error for an invalid direction")
+		}
+	})
+}
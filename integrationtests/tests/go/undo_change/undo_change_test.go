@@ -0,0 +1,156 @@
+package undo_change_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// setupJournal records changes in a fresh journal for the duration of a test
+func setupJournal(t *testing.T) {
+	journal, err := utilities.OpenJournal(t.TempDir(), 100)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	utilities.SetJournal(journal)
+	t.Cleanup(func() { utilities.SetJournal(nil) })
+}
+
+// TestUndoChange tests listing and undoing changes made through the tools
+func TestUndoChange(t *testing.T) {
+	t.Run("UndoRename", func(t *testing.T) {
+		suite := internal.GetTestSuite(t)
+		setupJournal(t)
+
+		time.Sleep(2 * time.Second)
+
+		ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+		defer cancel()
+
+		typesBefore, err := suite.ReadFile("types.go")
+		if err != nil {
+			t.Fatalf("Failed to read types.go: %v", err)
+		}
+		consumerBefore, err := suite.ReadFile("consumer.go")
+		if err != nil {
+			t.Fatalf("Failed to read consumer.go: %v", err)
+		}
+
+		filePath := filepath.Join(suite.WorkspaceDir, "types.go")
+		_, err = tools.RenameSymbol(ctx, suite.Client, filePath, 25, 7, "UpdatedConstant", false, false)
+		if err != nil {
+			t.Fatalf("RenameSymbol failed: %v", err)
+		}
+
+		list, err := tools.ListChanges(20)
+		if err != nil {
+			t.Fatalf("ListChanges failed: %v", err)
+		}
+		if !strings.Contains(list, "Change 1 at") || !strings.Contains(list, "to UpdatedConstant") ||
+			!strings.Contains(list, "consumer.go (modified)") {
+			t.Errorf("Expected the rename in the list of changes but got: %s", list)
+		}
+
+		result, err := tools.UndoChange(1, false)
+		if err != nil {
+			t.Fatalf("UndoChange failed: %v", err)
+		}
+
+		common.SnapshotTest(t, "go", "undo_change", "rename", result)
+
+		typesAfter, err := suite.ReadFile("types.go")
+		if err != nil {
+			t.Fatalf("Failed to read types.go: %v", err)
+		}
+		consumerAfter, err := suite.ReadFile("consumer.go")
+		if err != nil {
+			t.Fatalf("Failed to read consumer.go: %v", err)
+		}
+		if typesAfter != typesBefore || consumerAfter != consumerBefore {
+			t.Errorf("Expected the files to be restored by the undo")
+		}
+
+		list, err = tools.ListChanges(20)
+		if err != nil {
+			t.Fatalf("ListChanges failed: %v", err)
+		}
+		if !strings.Contains(list, "Change 1 at") || !strings.Contains(list, "[undone]") ||
+			!strings.Contains(list, "Undo change 1") {
+			t.Errorf("Expected the undo in the list of changes but got: %s", list)
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		suite := internal.GetTestSuite(t)
+		setupJournal(t)
+
+		ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+		defer cancel()
+
+		before, err := suite.ReadFile("clean.go")
+		if err != nil {
+			t.Fatalf("Failed to read clean.go: %v", err)
+		}
+
+		filePath := filepath.Join(suite.WorkspaceDir, "clean.go")
+		_, err = tools.ApplyTextEdits(ctx, suite.Client, filePath, []tools.TextEdit{
+			{StartLine: 1, EndLine: 1, NewText: "package main // edited"},
+		}, false)
+		if err != nil {
+			t.Fatalf("ApplyTextEdits failed: %v", err)
+		}
+
+		// Modify the file after the change, as another tool or the user would
+		if err := suite.WriteFile("clean.go", "package main\n"); err != nil {
+			t.Fatalf("Failed to write clean.go: %v", err)
+		}
+
+		_, err = tools.UndoChange(1, false)
+		if err == nil {
+			t.Fatalf("Expected a conflict error but got success")
+		}
+		if !strings.Contains(err.Error(), "clean.go: modified since the change") {
+			t.Errorf("Expected a conflict error but got: %v", err)
+		}
+
+		common.SnapshotTest(t, "go", "undo_change", "conflict", err.Error())
+
+		content, err := suite.ReadFile("clean.go")
+		if err != nil {
+			t.Fatalf("Failed to read clean.go: %v", err)
+		}
+		if content != "package main\n" {
+			t.Errorf("Expected the file to be unchanged after a conflict but got: %s", content)
+		}
+
+		_, err = tools.UndoChange(1, true)
+		if err != nil {
+			t.Fatalf("UndoChange with force failed: %v", err)
+		}
+
+		content, err = suite.ReadFile("clean.go")
+		if err != nil {
+			t.Fatalf("Failed to read clean.go: %v", err)
+		}
+		if content != before {
+			t.Errorf("Expected the original content after a forced undo but got: %s", content)
+		}
+	})
+
+	t.Run("UnknownChange", func(t *testing.T) {
+		internal.GetTestSuite(t)
+		setupJournal(t)
+
+		_, err := tools.UndoChange(42, false)
+		if err == nil || !strings.Contains(err.Error(), "change 42 not found") {
+			t.Errorf("Expected an error for an unknown change but got: %v", err)
+		}
+	})
+}
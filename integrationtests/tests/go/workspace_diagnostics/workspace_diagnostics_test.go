@@ -0,0 +1,129 @@
+package workspace_diagnostics_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestWorkspaceDiagnostics tests listing the diagnostics of the whole workspace
+func TestWorkspaceDiagnostics(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	// Analyzers only run on open files
+	filePath := filepath.Join(suite.WorkspaceDir, "main.go")
+	if err := suite.Client.OpenFile(suite.Context, filePath); err != nil {
+		t.Fatalf("Failed to open %s: %v", filePath, err)
+	}
+	suite.Client.WaitForDiagnostics(suite.Context, protocol.DocumentUri("file://"+filePath))
+
+	tests := []struct {
+		name           string
+		filter         tools.WorkspaceDiagnosticsFilter
+		limit          int
+		expectContains []string
+		expectMissing  []string
+		expectErr      string
+		snapshotName   string
+	}{
+		{
+			name:           "All",
+			limit:          100,
+			expectContains: []string{"Diagnostics in workspace: 3 in 2 files (1 error, 2 warnings)", "main.go\nDiagnostics in File: 2 (1 error, 1 warning)", "unreachable code", "not used in this module"},
+			snapshotName:   "all",
+		},
+		{
+			name:           "ErrorsOnly",
+			filter:         tools.WorkspaceDiagnosticsFilter{MinSeverity: "error"},
+			limit:          100,
+			expectContains: []string{"Diagnostics in workspace: 1 in 1 files (1 error)", "cannot use 3"},
+			expectMissing:  []string{"unreachable code"},
+			snapshotName:   "errors_only",
+		},
+		{
+			name:           "Source",
+			filter:         tools.WorkspaceDiagnosticsFilter{Source: "unreachable"},
+			limit:          100,
+			expectContains: []string{"unreachable code"},
+			expectMissing:  []string{"cannot use 3"},
+			snapshotName:   "source",
+		},
+		{
+			name:           "Code",
+			filter:         tools.WorkspaceDiagnosticsFilter{Code: "IncompatibleAssign"},
+			limit:          100,
+			expectContains: []string{"cannot use 3"},
+			expectMissing:  []string{"unreachable code"},
+			snapshotName:   "code",
+		},
+		{
+			name:           "PathGlob",
+			filter:         tools.WorkspaceDiagnosticsFilter{PathGlob: "*.go"},
+			limit:          100,
+			expectContains: []string{"main.go\nDiagnostics in File: 2 (1 error, 1 warning)"},
+			expectMissing:  []string{"go.mod"},
+			snapshotName:   "path_glob",
+		},
+		{
+			name:           "PathGlobWithoutDiagnostics",
+			filter:         tools.WorkspaceDiagnosticsFilter{PathGlob: "clean.go"},
+			limit:          100,
+			expectContains: []string{"No diagnostics found in the workspace"},
+			snapshotName:   "path_glob_without_diagnostics",
+		},
+		{
+			name:           "Limit",
+			limit:          1,
+			expectContains: []string{"Diagnostics in workspace: 3 in 2 files (1 error, 2 warnings)\nShowing the first 1"},
+			snapshotName:   "limit",
+		},
+		{
+			name:      "InvalidSeverity",
+			filter:    tools.WorkspaceDiagnosticsFilter{MinSeverity: "fatal"},
+			expectErr: "unknown severity",
+		},
+		{
+			name:      "InvalidPathGlob",
+			filter:    tools.WorkspaceDiagnosticsFilter{PathGlob: "[*.go"},
+			expectErr: "invalid pathGlob",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+			defer cancel()
+
+			result, err := tools.GetWorkspaceDiagnostics(ctx, suite.Client, tc.filter, tc.limit)
+			if tc.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("Expected error containing %q but got: %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetWorkspaceDiagnostics failed: %v", err)
+			}
+
+			for _, expected := range tc.expectContains {
+				if !strings.Contains(result, expected) {
+					t.Errorf("Expected result to contain %q but got:\n%s", expected, result)
+				}
+			}
+			for _, unexpected := range tc.expectMissing {
+				if strings.Contains(result, unexpected) {
+					t.Errorf("Expected result not to contain %q but got:\n%s", unexpected, result)
+				}
+			}
+
+			common.SnapshotTest(t, "go", "workspace_diagnostics", tc.snapshotName, result)
+		})
+	}
+}
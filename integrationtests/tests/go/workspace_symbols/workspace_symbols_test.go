@@ -0,0 +1,116 @@
+package workspace_symbols_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestSearchWorkspaceSymbols tests the SearchWorkspaceSymbols tool with Go symbols
+func TestSearchWorkspaceSymbols(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name           string
+		query          string
+		kinds          []string
+		pathGlob       string
+		limit          int
+		expectedText   string
+		unexpectedText string
+		snapshotName   string
+	}{
+		{
+			name:         "Query in workspace files",
+			query:        "SharedStruct",
+			pathGlob:     "workspace/*.go",
+			expectedText: "SharedStruct (Struct)",
+			snapshotName: "shared-struct",
+		},
+		{
+			name:           "Filter by kind",
+			query:          "Shared",
+			kinds:          []string{"Interface"},
+			pathGlob:       "workspace/*.go",
+			expectedText:   "SharedInterface (Interface)",
+			unexpectedText: "(Struct)",
+			snapshotName:   "interfaces",
+		},
+		{
+			name:           "Filter by path",
+			query:          "Consumer",
+			pathGlob:       "**/another_consumer.go",
+			expectedText:   "another_consumer.go",
+			unexpectedText: "/consumer.go",
+			snapshotName:   "path-glob",
+		},
+		{
+			name:         "Limit results",
+			query:        "Shared",
+			pathGlob:     "workspace/*.go",
+			limit:        1,
+			expectedText: "(showing first 1)",
+			snapshotName: "limit",
+		},
+		{
+			name:         "No results",
+			query:        "NotARealSymbolName",
+			pathGlob:     "workspace/*.go",
+			expectedText: "No symbols found",
+			snapshotName: "not-found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tools.SearchWorkspaceSymbols(ctx, suite.Client, tc.query, tc.kinds, tc.pathGlob, tc.limit)
+			if err != nil {
+				t.Fatalf("Failed to search workspace symbols: %v", err)
+			}
+
+			if !strings.Contains(result, tc.expectedText) {
+				t.Errorf("Workspace symbols do not contain expected text: %s\nGot: %s", tc.expectedText, result)
+			}
+			if tc.unexpectedText != "" && strings.Contains(result, tc.unexpectedText) {
+				t.Errorf("Workspace symbols contain unexpected text: %s\nGot: %s", tc.unexpectedText, result)
+			}
+
+			common.SnapshotTest(t, "go", "workspace_symbols", tc.snapshotName, result)
+		})
+	}
+}
+
+// TestSearchWorkspaceSymbolsInvalidKind tests that unknown symbol kinds are rejected
+func TestSearchWorkspaceSymbolsInvalidKind(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	_, err := tools.SearchWorkspaceSymbols(ctx, suite.Client, "Shared", []string{"NotAKind"}, "", 0)
+	if err == nil || !strings.Contains(err.Error(), "unknown symbol kind") {
+		t.Errorf("Expected unknown symbol kind error, got: %v", err)
+	}
+}
+
+// TestSearchWorkspaceSymbolsInvalidPathGlob tests that malformed path patterns are rejected
+// instead of matching nothing
+func TestSearchWorkspaceSymbolsInvalidPathGlob(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	_, err := tools.SearchWorkspaceSymbols(ctx, suite.Client, "Shared", nil, "[*.go", 0)
+	if err == nil || !strings.Contains(err.Error(), "invalid pathGlob") {
+		t.Errorf("Expected invalid pathGlob error, got: %v", err)
+	}
+}
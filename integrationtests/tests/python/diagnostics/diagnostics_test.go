@@ -26,7 +26,7 @@ func TestDiagnostics(t *testing.T) {
 
 		// Check diagnostics for clean.py, which shouldn't have any errors
 		filePath := filepath.Join(suite.WorkspaceDir, "clean.py")
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -52,7 +52,7 @@ func TestDiagnostics(t *testing.T) {
 
 		// Check diagnostics for error_file.py, which contains deliberate errors
 		filePath := filepath.Join(suite.WorkspaceDir, "error_file.py")
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -98,11 +98,8 @@ func TestDiagnostics(t *testing.T) {
 			t.Fatalf("Failed to open consumer_clean.py: %v", err)
 		}
 
-		// Wait for files to be processed
-		time.Sleep(2 * time.Second)
-
 		// Get initial diagnostics for consumer_clean.py
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -170,9 +167,6 @@ def get_items() -> List[str]:
 			t.Fatalf("Failed to send DidChangeWatchedFiles: %v", err)
 		}
 
-		// Wait for LSP to process the change
-		time.Sleep(3 * time.Second)
-
 		// Force reopen the consumer file to ensure LSP reevaluates it
 		err = suite.Client.CloseFile(ctx, consumerPath)
 		if err != nil {
@@ -184,11 +178,8 @@ def get_items() -> List[str]:
 			t.Fatalf("Failed to reopen consumer_clean.py: %v", err)
 		}
 
-		// Wait for diagnostics to be generated
-		time.Sleep(3 * time.Second)
-
 		// Check diagnostics again on consumer file - should now have an error
-		result, err = tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true)
+		result, err = tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed after dependency change: %v", err)
 		}
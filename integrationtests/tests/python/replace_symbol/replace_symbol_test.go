@@ -0,0 +1,51 @@
+package replace_symbol_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/integrationtests/tests/python/internal"
+	"github.com/isaacphi/mcp-language-server/internal/tools"
+)
+
+// TestReplaceSymbol tests replacing a method without touching the rest of its class
+func TestReplaceSymbol(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	filePath := filepath.Join(suite.WorkspaceDir, "clean.py")
+	newText := `    def get_name(self) -> str:
+        return "name: " + self.name`
+
+	result, err := tools.ReplaceSymbol(ctx, suite.Client, "get_name", filePath, newText)
+	if err != nil {
+		t.Fatalf("ReplaceSymbol failed: %v", err)
+	}
+
+	common.SnapshotTest(t, "python", "replace_symbol", "method", result)
+
+	content, err := suite.ReadFile("clean.py")
+	if err != nil {
+		t.Fatalf("Failed to read clean.py: %v", err)
+	}
+
+	// The rest of the class must be left in place
+	expected := []string{
+		"class CleanClass:\n    \"\"\"A clean class without errors.\"\"\"\n\n    def __init__(self, name: str):",
+		"        self.name = name\n\n    def get_name(self) -> str:\n        return \"name: \" + self.name\n\n    @staticmethod\n    def utility_method(items: list[int]) -> int:",
+	}
+	for _, e := range expected {
+		if !strings.Contains(content, e) {
+			t.Errorf("Expected clean.py to contain %q but got:\n%s", e, content)
+		}
+	}
+	if strings.Contains(content, "Get the name of this instance") {
+		t.Errorf("Expected the old method body to be replaced but got:\n%s", content)
+	}
+}
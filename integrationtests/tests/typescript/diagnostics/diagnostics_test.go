@@ -53,7 +53,7 @@ func TestDiagnostics(t *testing.T) {
 		// Target the clean file
 		filePath := filepath.Join(suite.WorkspaceDir, "clean.ts")
 
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, filePath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -102,7 +102,7 @@ console.log(result);
 		// Wait for diagnostics to be generated
 		time.Sleep(3 * time.Second)
 
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, testFilePath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, testFilePath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -136,7 +136,7 @@ console.log(result);
 		consumerPath := filepath.Join(suite.WorkspaceDir, "consumer.ts")
 
 		// Get initial diagnostics for consumer.ts
-		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true)
+		result, err := tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed: %v", err)
 		}
@@ -225,9 +225,6 @@ export enum SharedEnum {
 			t.Fatalf("Failed to send DidChangeWatchedFiles: %v", err)
 		}
 
-		// Wait for LSP to process the change
-		time.Sleep(3 * time.Second)
-
 		// Force reopen the consumer file to ensure LSP reevaluates it
 		err = suite.Client.CloseFile(ctx, consumerPath)
 		if err != nil {
@@ -239,11 +236,8 @@ export enum SharedEnum {
 			t.Fatalf("Failed to reopen consumer.ts: %v", err)
 		}
 
-		// Wait for diagnostics to be generated
-		time.Sleep(3 * time.Second)
-
 		// Check diagnostics again on consumer file - should now have an error
-		result, err = tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true)
+		result, err = tools.GetDiagnosticsForFile(ctx, suite.Client, consumerPath, 2, true, false)
 		if err != nil {
 			t.Fatalf("GetDiagnosticsForFile failed after dependency change: %v", err)
 		}
@@ -0,0 +1,109 @@
+package lsp
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// setServerCapabilities stores the capabilities the server announced in its initialize
+// result
+func (c *Client) setServerCapabilities(capabilities protocol.ServerCapabilities) {
+	c.capabilitiesMu.Lock()
+	defer c.capabilitiesMu.Unlock()
+	c.capabilities = capabilities
+}
+
+// ServerCapabilities returns the capabilities the server announced when it was initialized
+func (c *Client) ServerCapabilities() protocol.ServerCapabilities {
+	c.capabilitiesMu.RLock()
+	defer c.capabilitiesMu.RUnlock()
+	return c.capabilities
+}
+
+// SupportsCodeActionKind reports whether the server may return code actions of a kind.
+// Servers that support code actions without listing their kinds are assumed to support
+// all of them. A listed kind also covers its more and less specific kinds, for example
+// source covers source.organizeImports and source.organizeImports.ruff covers it too.
+func (c *Client) SupportsCodeActionKind(kind protocol.CodeActionKind) bool {
+	provider := c.ServerCapabilities().CodeActionProvider
+	switch v := provider.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	}
+
+	var options protocol.CodeActionOptions
+	if !decodeCapability(provider, &options) || len(options.CodeActionKinds) == 0 {
+		return true
+	}
+	for _, listed := range options.CodeActionKinds {
+		if codeActionKindCovers(listed, kind) || codeActionKindCovers(kind, listed) {
+			return true
+		}
+	}
+	return false
+}
+
+// codeActionKindCovers reports whether kind is parent or the same as parent, in the
+// hierarchy of dot separated code action kinds
+func codeActionKindCovers(parent, kind protocol.CodeActionKind) bool {
+	return kind == parent || strings.HasPrefix(string(kind), string(parent)+".")
+}
+
+// decodeCapability decodes a capability that was unmarshalled without a type, as a
+// boolean or options object, into the options type
+func decodeCapability(value any, options any) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, options) == nil
+}
+
+// SupportsPrepareRename reports whether the server announced support for
+// textDocument/prepareRename in its rename options
+func (c *Client) SupportsPrepareRename() bool {
+	provider := c.ServerCapabilities().RenameProvider
+	if provider == nil {
+		return false
+	}
+	if _, ok := provider.(bool); ok {
+		return false
+	}
+
+	var options protocol.RenameOptions
+	return decodeCapability(provider, &options) && options.PrepareProvider
+}
+
+// SupportsCompletionResolve reports whether the server announced support for
+// completionItem/resolve in its completion options
+func (c *Client) SupportsCompletionResolve() bool {
+	provider := c.ServerCapabilities().CompletionProvider
+	return provider != nil && provider.ResolveProvider
+}
+
+// SupportsPullDiagnostics reports whether the server announced support for pulling the
+// diagnostics of a file with textDocument/diagnostic
+func (c *Client) SupportsPullDiagnostics() bool {
+	provider := c.ServerCapabilities().DiagnosticProvider
+	return provider != nil && provider.Value != nil
+}
+
+// SupportsWorkspaceDiagnostics reports whether the server announced support for pulling
+// the diagnostics of the whole workspace with workspace/diagnostic
+func (c *Client) SupportsWorkspaceDiagnostics() bool {
+	provider := c.ServerCapabilities().DiagnosticProvider
+	if provider == nil {
+		return false
+	}
+	switch v := provider.Value.(type) {
+	case protocol.DiagnosticOptions:
+		return v.WorkspaceDiagnostics
+	case protocol.DiagnosticRegistrationOptions:
+		return v.WorkspaceDiagnostics
+	}
+	return false
+}
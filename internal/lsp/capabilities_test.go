@@ -0,0 +1,129 @@
+package lsp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+func TestSupportsCodeActionKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		kind     protocol.CodeActionKind
+		want     bool
+	}{
+		{"absent", `{}`, protocol.SourceOrganizeImports, false},
+		{"disabled", `{"codeActionProvider": false}`, protocol.SourceOrganizeImports, false},
+		{"enabled", `{"codeActionProvider": true}`, protocol.SourceOrganizeImports, true},
+		{"kinds not listed", `{"codeActionProvider": {}}`, protocol.SourceOrganizeImports, true},
+		{"listed", `{"codeActionProvider": {"codeActionKinds": ["quickfix", "source.organizeImports"]}}`, protocol.SourceOrganizeImports, true},
+		{"parent listed", `{"codeActionProvider": {"codeActionKinds": ["source"]}}`, protocol.SourceOrganizeImports, true},
+		{"child listed", `{"codeActionProvider": {"codeActionKinds": ["source.organizeImports.ruff"]}}`, protocol.SourceOrganizeImports, true},
+		{"not listed", `{"codeActionProvider": {"codeActionKinds": ["quickfix", "source.fixAll"]}}`, protocol.SourceOrganizeImports, false},
+		{"prefix is not a parent", `{"codeActionProvider": {"codeActionKinds": ["source.organize"]}}`, protocol.SourceOrganizeImports, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capabilities protocol.ServerCapabilities
+			if err := json.Unmarshal([]byte(tt.provider), &capabilities); err != nil {
+				t.Fatalf("Failed to unmarshal capabilities: %v", err)
+			}
+			client := &Client{}
+			client.setServerCapabilities(capabilities)
+
+			if got := client.SupportsCodeActionKind(tt.kind); got != tt.want {
+				t.Errorf("SupportsCodeActionKind(%q) = %v, want %v", tt.kind, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportsPrepareRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		want     bool
+	}{
+		{"absent", `{}`, false},
+		{"rename only", `{"renameProvider": true}`, false},
+		{"options without prepare", `{"renameProvider": {}}`, false},
+		{"prepare", `{"renameProvider": {"prepareProvider": true}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capabilities protocol.ServerCapabilities
+			if err := json.Unmarshal([]byte(tt.provider), &capabilities); err != nil {
+				t.Fatalf("Failed to unmarshal capabilities: %v", err)
+			}
+			client := &Client{}
+			client.setServerCapabilities(capabilities)
+
+			if got := client.SupportsPrepareRename(); got != tt.want {
+				t.Errorf("SupportsPrepareRename() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportsCompletionResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		want     bool
+	}{
+		{"absent", `{}`, false},
+		{"completion only", `{"completionProvider": {"triggerCharacters": ["."]}}`, false},
+		{"resolve", `{"completionProvider": {"resolveProvider": true}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capabilities protocol.ServerCapabilities
+			if err := json.Unmarshal([]byte(tt.provider), &capabilities); err != nil {
+				t.Fatalf("Failed to unmarshal capabilities: %v", err)
+			}
+			client := &Client{}
+			client.setServerCapabilities(capabilities)
+
+			if got := client.SupportsCompletionResolve(); got != tt.want {
+				t.Errorf("SupportsCompletionResolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportsPullDiagnostics(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  string
+		pull      bool
+		workspace bool
+	}{
+		{"absent", `{}`, false, false},
+		{"document", `{"diagnosticProvider": {"interFileDependencies": true, "workspaceDiagnostics": false}}`, true, false},
+		{"workspace", `{"diagnosticProvider": {"interFileDependencies": true, "workspaceDiagnostics": true}}`, true, true},
+		{"registration", `{"diagnosticProvider": {"id": "diagnostics", "documentSelector": null, "interFileDependencies": false, "workspaceDiagnostics": true}}`, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capabilities protocol.ServerCapabilities
+			if err := json.Unmarshal([]byte(tt.provider), &capabilities); err != nil {
+				t.Fatalf("Failed to unmarshal capabilities: %v", err)
+			}
+			client := &Client{}
+			client.setServerCapabilities(capabilities)
+
+			if got := client.SupportsPullDiagnostics(); got != tt.pull {
+				t.Errorf("SupportsPullDiagnostics() = %v, want %v", got, tt.pull)
+			}
+			if got := client.SupportsWorkspaceDiagnostics(); got != tt.workspace {
+				t.Errorf("SupportsWorkspaceDiagnostics() = %v, want %v", got, tt.workspace)
+			}
+		})
+	}
+}
@@ -20,14 +20,34 @@ type Client struct {
 	Cmd    *exec.Cmd
 	stdin  io.WriteCloser
 	stdout *bufio.Reader
-	stderr io.ReadCloser
+
+	// Serializes messages written to stdin and guards the connection while the server
+	// is restarted
+	writeMu sync.Mutex
+
+	// Starts the server process, and the workspace it was initialized with, so that it
+	// can be restarted by the supervisor if it exits
+	start        func() (*exec.Cmd, io.WriteCloser, io.Reader, error)
+	workspaceDir string
+
+	// Set once the server is asked to shut down or the client is closed, after which
+	// the server exiting is expected
+	closing atomic.Bool
+	closed  chan struct{}
 
 	// Request ID counter
 	nextID atomic.Int32
 
-	// Response handlers
-	handlers   map[string]chan *Message
-	handlersMu sync.RWMutex
+	// Response handlers, why the server is unavailable while it is restarted or after
+	// it could not be restarted, and how long to wait for responses
+	handlers       map[string]chan *Message
+	connErr        error
+	requestTimeout time.Duration
+	handlersMu     sync.RWMutex
+
+	// Capabilities announced by the server in its initialize result
+	capabilities   protocol.ServerCapabilities
+	capabilitiesMu sync.RWMutex
 
 	// Server request handlers
 	serverRequestHandlers map[string]ServerRequestHandler
@@ -37,50 +57,77 @@ type Client struct {
 	notificationHandlers map[string]NotificationHandler
 	notificationMu       sync.RWMutex
 
-	// Diagnostic cache
-	diagnostics   map[protocol.DocumentUri][]protocol.Diagnostic
-	diagnosticsMu sync.RWMutex
+	// File watchers registered by the server by registration ID, replayed to the file
+	// watch handler after a restart. The IDs registered by the old server are kept in
+	// replayedWatchers until the new one registers its own watchers, which replace them.
+	fileWatchers     map[string][]protocol.FileSystemWatcher
+	replayedWatchers map[string]bool
+	fileWatchersMu   sync.Mutex
+
+	// Diagnostic cache, with the time and document version diagnostics were last
+	// published for each file and a channel that is closed and replaced whenever they are.
+	// Diagnostics pulled with textDocument/diagnostic are kept apart, with the time and
+	// result ID of the last report.
+	diagnostics         map[protocol.DocumentUri][]protocol.Diagnostic
+	diagnosticsUpdated  map[protocol.DocumentUri]time.Time
+	diagnosticsVersion  map[protocol.DocumentUri]int32
+	pulledDiagnostics   map[protocol.DocumentUri][]protocol.Diagnostic
+	diagnosticsPulled   map[protocol.DocumentUri]time.Time
+	diagnosticsResultID map[protocol.DocumentUri]string
+	diagnosticsChanged  chan struct{}
+	diagnosticsTimeout  time.Duration
+	diagnosticsMu       sync.RWMutex
 
 	// Files are currently opened by the LSP
 	openFiles   map[string]*OpenFileInfo
 	openFilesMu sync.RWMutex
 }
 
+// NewClient starts a language server and a supervisor that restarts it if it exits
 func NewClient(command string, args ...string) (*Client, error) {
+	start := func() (*exec.Cmd, io.WriteCloser, io.Reader, error) {
+		return startServer(command, args...)
+	}
+
+	cmd, stdin, stdout, err := start()
+	if err != nil {
+		return nil, err
+	}
+
+	client := newClient(stdin, stdout)
+	client.Cmd = cmd
+	client.start = start
+
+	// Start message handling loop
+	go client.supervise(client.serve())
+
+	return client, nil
+}
+
+// startServer starts the language server process and logs its stderr
+func startServer(command string, args ...string) (*exec.Cmd, io.WriteCloser, io.Reader, error) {
 	cmd := exec.Command(command, args...)
 	// Copy env
 	cmd.Env = os.Environ()
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create stdin pipe: %w", err)
 	}
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
-	}
-
-	client := &Client{
-		Cmd:                   cmd,
-		stdin:                 stdin,
-		stdout:                bufio.NewReader(stdout),
-		stderr:                stderr,
-		handlers:              make(map[string]chan *Message),
-		notificationHandlers:  make(map[string]NotificationHandler),
-		serverRequestHandlers: make(map[string]ServerRequestHandler),
-		diagnostics:           make(map[protocol.DocumentUri][]protocol.Diagnostic),
-		openFiles:             make(map[string]*OpenFileInfo),
+		return nil, nil, nil, fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
 	// Start the LSP server process
 	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start LSP server: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to start LSP server: %w", err)
 	}
 
 	// Handle stderr in a separate goroutine with proper logging
@@ -95,10 +142,31 @@ func NewClient(command string, args ...string) (*Client, error) {
 		}
 	}()
 
-	// Start message handling loop
-	go client.handleMessages()
+	return cmd, stdin, stdout, nil
+}
 
-	return client, nil
+// newClient creates a client that exchanges messages with a server over stdin and stdout.
+// The caller starts the message handling loop.
+func newClient(stdin io.WriteCloser, stdout io.Reader) *Client {
+	return &Client{
+		stdin:                 stdin,
+		stdout:                bufio.NewReader(stdout),
+		closed:                make(chan struct{}),
+		handlers:              make(map[string]chan *Message),
+		requestTimeout:        DefaultRequestTimeout,
+		notificationHandlers:  make(map[string]NotificationHandler),
+		serverRequestHandlers: make(map[string]ServerRequestHandler),
+		fileWatchers:          make(map[string][]protocol.FileSystemWatcher),
+		diagnostics:           make(map[protocol.DocumentUri][]protocol.Diagnostic),
+		diagnosticsUpdated:    make(map[protocol.DocumentUri]time.Time),
+		diagnosticsVersion:    make(map[protocol.DocumentUri]int32),
+		pulledDiagnostics:     make(map[protocol.DocumentUri][]protocol.Diagnostic),
+		diagnosticsPulled:     make(map[protocol.DocumentUri]time.Time),
+		diagnosticsResultID:   make(map[protocol.DocumentUri]string),
+		diagnosticsChanged:    make(chan struct{}),
+		diagnosticsTimeout:    DefaultDiagnosticsTimeout,
+		openFiles:             make(map[string]*OpenFileInfo),
+	}
 }
 
 func (c *Client) RegisterNotificationHandler(method string, handler NotificationHandler) {
@@ -114,6 +182,59 @@ func (c *Client) RegisterServerRequestHandler(method string, handler ServerReque
 }
 
 func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (*protocol.InitializeResult, error) {
+	c.workspaceDir = workspaceDir
+
+	result, err := c.initialize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.initializeServerSpecific(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// initializeServerSpecific runs the setup some servers need once they are initialized.
+// It goes through the checks that the server is available, so a restarted server must
+// be marked as available first.
+func (c *Client) initializeServerSpecific(ctx context.Context) error {
+	c.writeMu.Lock()
+	cmd := c.Cmd
+	c.writeMu.Unlock()
+	if cmd == nil {
+		return nil
+	}
+
+	// LSP sepecific Initialization
+	path := strings.ToLower(cmd.Path)
+	switch {
+	case strings.Contains(path, "typescript-language-server"):
+		err := initializeTypescriptLanguageServer(ctx, c, c.workspaceDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initialize runs the initialize handshake with the server for the client's workspace.
+// It bypasses the check that the server is available, so that the supervisor can
+// initialize a restarted server.
+func (c *Client) initialize(ctx context.Context) (*protocol.InitializeResult, error) {
+	workspaceDir := c.workspaceDir
+
+	// Shared by pushed and pulled diagnostics, so that servers include related locations,
+	// tags and links to documentation in both
+	diagnosticsCapabilities := protocol.DiagnosticsCapabilities{
+		RelatedInformation: true,
+		TagSupport: &protocol.ClientDiagnosticsTagOptions{
+			ValueSet: []protocol.DiagnosticTag{protocol.Unnecessary, protocol.Deprecated},
+		},
+		CodeDescriptionSupport: true,
+	}
 	initParams := &protocol.InitializeParams{
 		WorkspaceFoldersInitializeParams: protocol.WorkspaceFoldersInitializeParams{
 			WorkspaceFolders: []protocol.WorkspaceFolder{
@@ -149,21 +270,61 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 						DidSave:             true,
 					},
 					Completion: protocol.CompletionClientCapabilities{
-						CompletionItem: protocol.ClientCompletionItemOptions{},
+						CompletionItem: protocol.ClientCompletionItemOptions{
+							DocumentationFormat: []protocol.MarkupKind{protocol.Markdown, protocol.PlainText},
+							DeprecatedSupport:   true,
+							LabelDetailsSupport: true,
+							ResolveSupport: &protocol.ClientCompletionItemResolveOptions{
+								Properties: []string{"documentation", "detail"},
+							},
+						},
+					},
+					SignatureHelp: &protocol.SignatureHelpClientCapabilities{
+						SignatureInformation: &protocol.ClientSignatureInformationOptions{
+							DocumentationFormat:    []protocol.MarkupKind{protocol.Markdown, protocol.PlainText},
+							ActiveParameterSupport: true,
+						},
 					},
 					CodeLens: &protocol.CodeLensClientCapabilities{
 						DynamicRegistration: true,
 					},
-					DocumentSymbol: protocol.DocumentSymbolClientCapabilities{},
+					DocumentSymbol: protocol.DocumentSymbolClientCapabilities{
+						HierarchicalDocumentSymbolSupport: true,
+					},
 					CodeAction: protocol.CodeActionClientCapabilities{
 						CodeActionLiteralSupport: protocol.ClientCodeActionLiteralOptions{
 							CodeActionKind: protocol.ClientCodeActionKindOptions{
-								ValueSet: []protocol.CodeActionKind{},
+								ValueSet: []protocol.CodeActionKind{
+									protocol.Empty,
+									protocol.QuickFix,
+									protocol.Refactor,
+									protocol.RefactorExtract,
+									protocol.RefactorInline,
+									protocol.RefactorRewrite,
+									protocol.Source,
+									protocol.SourceOrganizeImports,
+									protocol.SourceFixAll,
+								},
 							},
 						},
+						IsPreferredSupport: true,
+						DisabledSupport:    true,
+						DataSupport:        true,
+						ResolveSupport: &protocol.ClientCodeActionResolveOptions{
+							Properties: []string{"edit"},
+						},
+					},
+					InlayHint: &protocol.InlayHintClientCapabilities{},
+					Rename: &protocol.RenameClientCapabilities{
+						PrepareSupport: true,
 					},
 					PublishDiagnostics: protocol.PublishDiagnosticsClientCapabilities{
-						VersionSupport: true,
+						VersionSupport:          true,
+						DiagnosticsCapabilities: diagnosticsCapabilities,
+					},
+					Diagnostic: &protocol.DiagnosticClientCapabilities{
+						RelatedDocumentSupport:  true,
+						DiagnosticsCapabilities: diagnosticsCapabilities,
 					},
 					SemanticTokens: protocol.SemanticTokensClientCapabilities{
 						Requests: protocol.ClientSemanticTokensRequestOptions{
@@ -187,43 +348,50 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 					"vendor":             true,
 					"vulncheck":          false,
 				},
+				// Publish all diagnostics as soon as they are computed, rather than the
+				// quick ones first and the analyzers' a second later, so that waiting for
+				// the diagnostics of the current version gets all of them
+				"diagnosticsDelay": "0s",
+				"hints": map[string]bool{
+					"assignVariableTypes":    true,
+					"compositeLiteralFields": true,
+					"compositeLiteralTypes":  true,
+					"constantValues":         true,
+					"functionTypeParameters": true,
+					"parameterNames":         true,
+					"rangeVariableTypes":     true,
+				},
 			},
 		},
 	}
 
 	var result protocol.InitializeResult
-	if err := c.Call(ctx, "initialize", initParams, &result); err != nil {
+	if err := c.call(ctx, "initialize", initParams, &result); err != nil {
 		return nil, fmt.Errorf("initialize failed: %w", err)
 	}
+	c.setServerCapabilities(result.Capabilities)
 
-	if err := c.Notify(ctx, "initialized", struct{}{}); err != nil {
+	if err := c.notify(ctx, "initialized", struct{}{}); err != nil {
 		return nil, fmt.Errorf("initialized notification failed: %w", err)
 	}
 
 	// Register handlers
 	c.RegisterServerRequestHandler("workspace/applyEdit", HandleApplyEdit)
 	c.RegisterServerRequestHandler("workspace/configuration", HandleWorkspaceConfiguration)
-	c.RegisterServerRequestHandler("client/registerCapability", HandleRegisterCapability)
+	c.RegisterServerRequestHandler("client/registerCapability",
+		func(params json.RawMessage) (any, error) { return HandleRegisterCapability(c, params) })
+	c.RegisterServerRequestHandler("client/unregisterCapability",
+		func(params json.RawMessage) (any, error) { return HandleUnregisterCapability(c, params) })
 	c.RegisterNotificationHandler("window/showMessage", HandleServerMessage)
 	c.RegisterNotificationHandler("textDocument/publishDiagnostics",
 		func(params json.RawMessage) { HandleDiagnostics(c, params) })
 
 	// Notify the LSP server
-	err := c.Initialized(ctx, protocol.InitializedParams{})
+	err := c.notify(ctx, "initialized", protocol.InitializedParams{})
 	if err != nil {
 		return nil, fmt.Errorf("initialization failed: %w", err)
 	}
 
-	// LSP sepecific Initialization
-	path := strings.ToLower(c.Cmd.Path)
-	switch {
-	case strings.Contains(path, "typescript-language-server"):
-		err := initializeTypescriptLanguageServer(ctx, c, workspaceDir)
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	return &result, nil
 }
 
@@ -235,14 +403,26 @@ func (c *Client) Close() error {
 	// Attempt to close files but continue shutdown regardless
 	c.CloseAllFiles(ctx)
 
+	// The server exiting from now on is expected, stop the supervisor from restarting it
+	c.closing.Store(true)
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+
+	c.writeMu.Lock()
+	cmd, stdin := c.Cmd, c.stdin
+	c.writeMu.Unlock()
+
 	// Force kill the LSP process if it doesn't exit within timeout
 	forcedKill := make(chan struct{})
 	go func() {
 		select {
 		case <-time.After(2 * time.Second):
 			lspLogger.Warn("LSP process did not exit within timeout, forcing kill")
-			if c.Cmd.Process != nil {
-				if err := c.Cmd.Process.Kill(); err != nil {
+			if cmd.Process != nil {
+				if err := cmd.Process.Kill(); err != nil {
 					lspLogger.Error("Failed to kill process: %v", err)
 				} else {
 					lspLogger.Info("Process killed successfully")
@@ -256,12 +436,12 @@ func (c *Client) Close() error {
 	}()
 
 	// Close stdin to signal the server
-	if err := c.stdin.Close(); err != nil {
+	if err := stdin.Close(); err != nil {
 		lspLogger.Error("Failed to close stdin: %v", err)
 	}
 
 	// Wait for process to exit
-	err := c.Cmd.Wait()
+	err := cmd.Wait()
 	close(forcedKill) // Stop the force kill goroutine
 
 	return err
@@ -284,6 +464,9 @@ func (c *Client) WaitForServerReady(ctx context.Context) error {
 type OpenFileInfo struct {
 	Version int32
 	URI     protocol.DocumentUri
+
+	// changed is when the server was last sent the content of the file
+	changed time.Time
 }
 
 func (c *Client) OpenFile(ctx context.Context, filepath string) error {
@@ -311,6 +494,7 @@ func (c *Client) OpenFile(ctx context.Context, filepath string) error {
 		},
 	}
 
+	opened := time.Now()
 	if err := c.Notify(ctx, "textDocument/didOpen", params); err != nil {
 		return err
 	}
@@ -319,6 +503,7 @@ func (c *Client) OpenFile(ctx context.Context, filepath string) error {
 	c.openFiles[uri] = &OpenFileInfo{
 		Version: 1,
 		URI:     protocol.DocumentUri(uri),
+		changed: opened,
 	}
 	c.openFilesMu.Unlock()
 
@@ -328,13 +513,19 @@ func (c *Client) OpenFile(ctx context.Context, filepath string) error {
 }
 
 func (c *Client) NotifyChange(ctx context.Context, filepath string) error {
-	uri := fmt.Sprintf("file://%s", filepath)
-
 	content, err := os.ReadFile(filepath)
 	if err != nil {
 		return fmt.Errorf("error reading file: %w", err)
 	}
 
+	return c.NotifyContent(ctx, filepath, string(content))
+}
+
+// NotifyContent sends new content for an open file to the server without writing it to disk.
+// NotifyChange restores the server's view of the file to the content on disk.
+func (c *Client) NotifyContent(ctx context.Context, filepath string, content string) error {
+	uri := fmt.Sprintf("file://%s", filepath)
+
 	c.openFilesMu.Lock()
 	fileInfo, isOpen := c.openFiles[uri]
 	if !isOpen {
@@ -344,6 +535,7 @@ func (c *Client) NotifyChange(ctx context.Context, filepath string) error {
 
 	// Increment version
 	fileInfo.Version++
+	fileInfo.changed = time.Now()
 	version := fileInfo.Version
 	c.openFilesMu.Unlock()
 
@@ -357,7 +549,7 @@ func (c *Client) NotifyChange(ctx context.Context, filepath string) error {
 		ContentChanges: []protocol.TextDocumentContentChangeEvent{
 			{
 				Value: protocol.TextDocumentContentChangeWholeDocument{
-					Text: string(content),
+					Text: content,
 				},
 			},
 		},
@@ -390,6 +582,9 @@ func (c *Client) CloseFile(ctx context.Context, filepath string) error {
 	delete(c.openFiles, uri)
 	c.openFilesMu.Unlock()
 
+	// Versions start over when the file is opened again
+	c.forgetDiagnosticsVersion(protocol.DocumentUri(uri))
+
 	return nil
 }
 
@@ -401,6 +596,18 @@ func (c *Client) IsFileOpen(filepath string) bool {
 	return exists
 }
 
+// OpenFileURIs returns the URIs of the files that are open in the server
+func (c *Client) OpenFileURIs() []protocol.DocumentUri {
+	c.openFilesMu.RLock()
+	defer c.openFilesMu.RUnlock()
+
+	uris := make([]protocol.DocumentUri, 0, len(c.openFiles))
+	for _, fileInfo := range c.openFiles {
+		uris = append(uris, fileInfo.URI)
+	}
+	return uris
+}
+
 // CloseAllFiles closes all currently open files
 func (c *Client) CloseAllFiles(ctx context.Context) {
 	c.openFilesMu.Lock()
@@ -424,10 +631,3 @@ func (c *Client) CloseAllFiles(ctx context.Context) {
 
 	lspLogger.Debug("Closed %d files", len(filesToClose))
 }
-
-func (c *Client) GetFileDiagnostics(uri protocol.DocumentUri) []protocol.Diagnostic {
-	c.diagnosticsMu.RLock()
-	defer c.diagnosticsMu.RUnlock()
-
-	return c.diagnostics[uri]
-}
@@ -0,0 +1,344 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// DefaultDiagnosticsTimeout is how long to wait for the server to check a file before
+// using the diagnostics that are cached for it
+const DefaultDiagnosticsTimeout = 10 * time.Second
+
+// SetDiagnosticsTimeout sets how long WaitForDiagnostics waits for the server
+func (c *Client) SetDiagnosticsTimeout(timeout time.Duration) {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+	c.diagnosticsTimeout = timeout
+}
+
+// DiagnosticsTimeout returns how long WaitForDiagnostics waits for the server
+func (c *Client) DiagnosticsTimeout() time.Duration {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+	return c.diagnosticsTimeout
+}
+
+// GetFileDiagnostics returns the diagnostics of a file, both published by the server and
+// pulled with textDocument/diagnostic
+func (c *Client) GetFileDiagnostics(uri protocol.DocumentUri) []protocol.Diagnostic {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+
+	return c.fileDiagnostics(uri)
+}
+
+// AllDiagnostics returns the diagnostics of every file the server has reported
+// diagnostics for, both published and pulled
+func (c *Client) AllDiagnostics() map[protocol.DocumentUri][]protocol.Diagnostic {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+
+	all := make(map[protocol.DocumentUri][]protocol.Diagnostic, len(c.diagnostics))
+	for uri := range c.diagnostics {
+		all[uri] = c.fileDiagnostics(uri)
+	}
+	for uri := range c.pulledDiagnostics {
+		if _, ok := all[uri]; !ok {
+			all[uri] = c.fileDiagnostics(uri)
+		}
+	}
+	return all
+}
+
+// fileDiagnostics merges the published and pulled diagnostics of a file. Published
+// diagnostics computed before the last change of an open file are left out once
+// diagnostics were pulled for the new content, as they may describe code that is gone.
+// The caller must hold diagnosticsMu.
+func (c *Client) fileDiagnostics(uri protocol.DocumentUri) []protocol.Diagnostic {
+	published := c.diagnostics[uri]
+	if pulled, ok := c.diagnosticsPulled[uri]; ok && len(published) > 0 {
+		version, changed, isOpen := c.openFileVersion(uri)
+		if isOpen && pulled.After(changed) && !c.publishedCurrent(uri, version, changed) {
+			published = nil
+		}
+	}
+	return mergeDiagnostics(published, c.pulledDiagnostics[uri])
+}
+
+// mergeDiagnostics appends the pulled diagnostics that weren't also published, for
+// servers that support both models
+func mergeDiagnostics(published, pulled []protocol.Diagnostic) []protocol.Diagnostic {
+	if len(pulled) == 0 {
+		return published
+	}
+	if len(published) == 0 {
+		return pulled
+	}
+
+	seen := make(map[string]bool, len(published))
+	for _, diag := range published {
+		seen[diagnosticKey(diag)] = true
+	}
+	merged := append([]protocol.Diagnostic(nil), published...)
+	for _, diag := range pulled {
+		if !seen[diagnosticKey(diag)] {
+			merged = append(merged, diag)
+		}
+	}
+	return merged
+}
+
+func diagnosticKey(diag protocol.Diagnostic) string {
+	return fmt.Sprintf("%d:%d-%d:%d\x00%d\x00%s\x00%v\x00%s",
+		diag.Range.Start.Line, diag.Range.Start.Character, diag.Range.End.Line, diag.Range.End.Character,
+		diag.Severity, diag.Source, diag.Code, diag.Message)
+}
+
+// notifyDiagnosticsChanged wakes up any waiters. The caller must hold diagnosticsMu.
+func (c *Client) notifyDiagnosticsChanged() {
+	close(c.diagnosticsChanged)
+	c.diagnosticsChanged = make(chan struct{})
+}
+
+// setDiagnostics stores the diagnostics published for a file. version is the document
+// version they were computed for, or 0 if the server didn't say.
+func (c *Client) setDiagnostics(uri protocol.DocumentUri, diagnostics []protocol.Diagnostic, version int32) {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+
+	c.diagnostics[uri] = diagnostics
+	c.diagnosticsUpdated[uri] = time.Now()
+	if version != 0 {
+		c.diagnosticsVersion[uri] = version
+	} else {
+		delete(c.diagnosticsVersion, uri)
+	}
+	c.notifyDiagnosticsChanged()
+}
+
+// forgetDiagnosticsVersion forgets the document version the published diagnostics of a
+// file were computed for, so that they aren't taken for those of a later version
+func (c *Client) forgetDiagnosticsVersion(uri protocol.DocumentUri) {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+	delete(c.diagnosticsVersion, uri)
+}
+
+// clearDiagnostics forgets the diagnostics of all files, pushed and pulled
+func (c *Client) clearDiagnostics() {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+
+	c.diagnostics = make(map[protocol.DocumentUri][]protocol.Diagnostic)
+	c.diagnosticsUpdated = make(map[protocol.DocumentUri]time.Time)
+	c.diagnosticsVersion = make(map[protocol.DocumentUri]int32)
+	c.pulledDiagnostics = make(map[protocol.DocumentUri][]protocol.Diagnostic)
+	c.diagnosticsPulled = make(map[protocol.DocumentUri]time.Time)
+	c.diagnosticsResultID = make(map[protocol.DocumentUri]string)
+	c.notifyDiagnosticsChanged()
+}
+
+// setPulledDiagnostics stores a pulled diagnostic report for a file. A full report
+// replaces the pulled diagnostics of the file, an unchanged report confirms them.
+func (c *Client) setPulledDiagnostics(uri protocol.DocumentUri, report protocol.FullDocumentDiagnosticReport) {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+
+	switch protocol.DocumentDiagnosticReportKind(report.Kind) {
+	case protocol.DiagnosticFull:
+		c.pulledDiagnostics[uri] = report.Items
+	case protocol.DiagnosticUnchanged:
+		if _, ok := c.diagnosticsPulled[uri]; !ok {
+			// Nothing to confirm, the next request must not send a result ID
+			lspLogger.Warn("Unchanged diagnostic report for %s without a previous report", uri)
+			delete(c.diagnosticsResultID, uri)
+			return
+		}
+	default:
+		// Not all servers fill in the kind, their reports can't be told apart
+		lspLogger.Debug("Ignoring diagnostic report for %s with unknown kind %q", uri, report.Kind)
+		return
+	}
+
+	if report.ResultID != "" {
+		c.diagnosticsResultID[uri] = report.ResultID
+	} else {
+		delete(c.diagnosticsResultID, uri)
+	}
+	c.diagnosticsPulled[uri] = time.Now()
+	c.notifyDiagnosticsChanged()
+}
+
+// diagnosticsCurrent reports whether the cached diagnostics of a file were computed for
+// the content last sent to the server. For files that aren't open, any diagnostics are
+// current. The caller must hold diagnosticsMu.
+func (c *Client) diagnosticsCurrent(uri protocol.DocumentUri) bool {
+	_, published := c.diagnosticsUpdated[uri]
+	pulled, wasPulled := c.diagnosticsPulled[uri]
+	if !published && !wasPulled {
+		return false
+	}
+
+	version, changed, isOpen := c.openFileVersion(uri)
+	if !isOpen || (wasPulled && pulled.After(changed)) {
+		return true
+	}
+	return c.publishedCurrent(uri, version, changed)
+}
+
+// publishedCurrent reports whether the published diagnostics of a file were computed for
+// the given version of an open file, last changed at the given time. The caller must hold
+// diagnosticsMu.
+func (c *Client) publishedCurrent(uri protocol.DocumentUri, version int32, changed time.Time) bool {
+	updated, ok := c.diagnosticsUpdated[uri]
+	if !ok {
+		return false
+	}
+	// Prefer the version when the server reports it, as diagnostics for an older version
+	// may still arrive after a change
+	if published, ok := c.diagnosticsVersion[uri]; ok {
+		return published >= version
+	}
+	return updated.After(changed)
+}
+
+// openFileVersion returns the version last sent to the server of an open file and when
+// it was sent
+func (c *Client) openFileVersion(uri protocol.DocumentUri) (version int32, changed time.Time, isOpen bool) {
+	c.openFilesMu.RLock()
+	defer c.openFilesMu.RUnlock()
+
+	fileInfo, isOpen := c.openFiles[string(uri)]
+	if !isOpen {
+		return 0, time.Time{}, false
+	}
+	return fileInfo.Version, fileInfo.changed, true
+}
+
+// WaitForDiagnostics waits until the diagnostics of every file in uris are current, that
+// is until the server has published diagnostics for the version of each open file it was
+// last sent. It gives up after the diagnostics timeout or when ctx is done, and returns
+// the files whose diagnostics may be out of date.
+func (c *Client) WaitForDiagnostics(ctx context.Context, uris ...protocol.DocumentUri) []protocol.DocumentUri {
+	ctx, cancel := context.WithTimeout(ctx, c.DiagnosticsTimeout())
+	defer cancel()
+
+	for {
+		c.diagnosticsMu.RLock()
+		var pending []protocol.DocumentUri
+		for _, uri := range uris {
+			if !c.diagnosticsCurrent(uri) {
+				pending = append(pending, uri)
+			}
+		}
+		changed := c.diagnosticsChanged
+		c.diagnosticsMu.RUnlock()
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			lspLogger.Debug("Timed out waiting for diagnostics for %v", pending)
+			return pending
+		case <-changed:
+		}
+	}
+}
+
+// PullDiagnostics requests the diagnostics of a file with textDocument/diagnostic, passing
+// the result ID of the previous report, and stores the report and those of any related
+// documents in the diagnostics cache, which makes them current
+func (c *Client) PullDiagnostics(ctx context.Context, uri protocol.DocumentUri) error {
+	c.diagnosticsMu.RLock()
+	previousResultID := c.diagnosticsResultID[uri]
+	c.diagnosticsMu.RUnlock()
+
+	report, err := c.Diagnostic(ctx, protocol.DocumentDiagnosticParams{
+		TextDocument:     protocol.TextDocumentIdentifier{URI: uri},
+		PreviousResultID: previousResultID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var relatedDocuments map[protocol.DocumentUri]interface{}
+	switch v := report.Value.(type) {
+	case protocol.RelatedFullDocumentDiagnosticReport:
+		// Unchanged reports also decode as full reports, so rely on their kind
+		c.setPulledDiagnostics(uri, v.FullDocumentDiagnosticReport)
+		relatedDocuments = v.RelatedDocuments
+	case protocol.RelatedUnchangedDocumentDiagnosticReport:
+		c.setPulledDiagnostics(uri, protocol.FullDocumentDiagnosticReport{
+			Kind:     v.Kind,
+			ResultID: v.ResultID,
+		})
+		relatedDocuments = v.RelatedDocuments
+	case nil:
+		return fmt.Errorf("empty diagnostic report")
+	default:
+		return fmt.Errorf("unexpected diagnostic report type: %T", v)
+	}
+
+	for relatedURI, value := range relatedDocuments {
+		related, err := decodeDiagnosticReport(value)
+		if err != nil {
+			lspLogger.Warn("Invalid diagnostic report for related document %s: %v", relatedURI, err)
+			continue
+		}
+		c.setPulledDiagnostics(relatedURI, related)
+	}
+	return nil
+}
+
+// decodeDiagnosticReport decodes a full or unchanged report of a related document, which
+// is left as generic JSON values by the protocol types
+func decodeDiagnosticReport(value interface{}) (protocol.FullDocumentDiagnosticReport, error) {
+	var report protocol.FullDocumentDiagnosticReport
+	data, err := json.Marshal(value)
+	if err != nil {
+		return report, err
+	}
+	err = json.Unmarshal(data, &report)
+	return report, err
+}
+
+// PullWorkspaceDiagnostics requests the diagnostics of the whole workspace with
+// workspace/diagnostic, passing the result IDs of the previous reports, and stores the
+// reports in the diagnostics cache
+func (c *Client) PullWorkspaceDiagnostics(ctx context.Context) error {
+	c.diagnosticsMu.RLock()
+	previousResultIDs := make([]protocol.PreviousResultId, 0, len(c.diagnosticsResultID))
+	for uri, id := range c.diagnosticsResultID {
+		previousResultIDs = append(previousResultIDs, protocol.PreviousResultId{URI: uri, Value: id})
+	}
+	c.diagnosticsMu.RUnlock()
+
+	report, err := c.DiagnosticWorkspace(ctx, protocol.WorkspaceDiagnosticParams{
+		PreviousResultIds: previousResultIDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range report.Items {
+		switch v := item.Value.(type) {
+		case protocol.WorkspaceFullDocumentDiagnosticReport:
+			// Unchanged reports also decode as full reports, so rely on their kind
+			c.setPulledDiagnostics(v.URI, v.FullDocumentDiagnosticReport)
+		case protocol.WorkspaceUnchangedDocumentDiagnosticReport:
+			c.setPulledDiagnostics(v.URI, protocol.FullDocumentDiagnosticReport{
+				Kind:     v.Kind,
+				ResultID: v.ResultID,
+			})
+		default:
+			lspLogger.Warn("Unexpected workspace diagnostic report type: %T", v)
+		}
+	}
+	return nil
+}
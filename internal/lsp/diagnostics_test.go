@@ -0,0 +1,228 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// newTestClient returns a client connected to a fake server that answers each request
+// with the result of handle
+func newTestClient(t *testing.T, handle func(method string, params json.RawMessage) any) *Client {
+	stdin, stdout, _ := startTestServer(t, handle)
+	client := newClient(stdin, stdout)
+	go client.handleMessages()
+	return client
+}
+
+// noResponse is returned by a fake server handler to leave a request unanswered
+type noResponse struct{}
+
+// startTestServer starts a fake server that passes each request and notification to
+// handle and answers requests with the result, unless it is a noResponse. The returned
+// function makes the server exit.
+func startTestServer(t *testing.T, handle func(method string, params json.RawMessage) any) (io.WriteCloser, io.Reader, func()) {
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+
+	go func() {
+		reader := bufio.NewReader(serverIn)
+		for {
+			msg, err := ReadMessage(reader)
+			if err != nil {
+				return
+			}
+			if msg.Method == "" {
+				continue
+			}
+			response := handle(msg.Method, msg.Params)
+			if _, ok := response.(noResponse); msg.ID == nil || ok {
+				continue
+			}
+			result, err := json.Marshal(response)
+			if err != nil {
+				t.Errorf("Failed to marshal result: %v", err)
+				return
+			}
+			if err := WriteMessage(serverOut, &Message{JSONRPC: "2.0", ID: msg.ID, Result: result}); err != nil {
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		clientOut.Close()
+		serverOut.Close()
+	}
+	t.Cleanup(stop)
+	return clientOut, clientIn, stop
+}
+
+func testDiagnostic(message string) protocol.Diagnostic {
+	return protocol.Diagnostic{Severity: protocol.SeverityError, Message: message}
+}
+
+func TestPullDiagnostics(t *testing.T) {
+	const uri = protocol.DocumentUri("file:///test/file.go")
+	const related = protocol.DocumentUri("file:///test/related.go")
+
+	responses := []any{
+		map[string]any{
+			"kind":     "full",
+			"resultId": "1",
+			"items":    []protocol.Diagnostic{testDiagnostic("first")},
+			"relatedDocuments": map[string]any{
+				string(related): map[string]any{
+					"kind":  "full",
+					"items": []protocol.Diagnostic{testDiagnostic("related")},
+				},
+			},
+		},
+		map[string]any{"kind": "unchanged", "resultId": "2"},
+		map[string]any{"kind": "full", "items": []protocol.Diagnostic{}},
+	}
+	var previousResultIDs []string
+	client := newTestClient(t, func(method string, params json.RawMessage) any {
+		var p protocol.DocumentDiagnosticParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			t.Errorf("Failed to unmarshal params: %v", err)
+		}
+		previousResultIDs = append(previousResultIDs, p.PreviousResultID)
+		response := responses[0]
+		responses = responses[1:]
+		return response
+	})
+	ctx := context.Background()
+
+	if err := client.PullDiagnostics(ctx, uri); err != nil {
+		t.Fatalf("PullDiagnostics failed: %v", err)
+	}
+	if got := client.GetFileDiagnostics(uri); len(got) != 1 || got[0].Message != "first" {
+		t.Errorf("Expected the full report to be cached, got %v", got)
+	}
+	if got := client.GetFileDiagnostics(related); len(got) != 1 || got[0].Message != "related" {
+		t.Errorf("Expected the related document report to be cached, got %v", got)
+	}
+
+	// An unchanged report keeps the cached diagnostics
+	if err := client.PullDiagnostics(ctx, uri); err != nil {
+		t.Fatalf("PullDiagnostics failed: %v", err)
+	}
+	if got := client.GetFileDiagnostics(uri); len(got) != 1 || got[0].Message != "first" {
+		t.Errorf("Expected an unchanged report to keep the diagnostics, got %v", got)
+	}
+
+	// A full report replaces them
+	if err := client.PullDiagnostics(ctx, uri); err != nil {
+		t.Fatalf("PullDiagnostics failed: %v", err)
+	}
+	if got := client.GetFileDiagnostics(uri); len(got) != 0 {
+		t.Errorf("Expected an empty full report to clear the diagnostics, got %v", got)
+	}
+
+	expectedIDs := []string{"", "1", "2"}
+	for i, id := range expectedIDs {
+		if i >= len(previousResultIDs) || previousResultIDs[i] != id {
+			t.Errorf("Expected previous result IDs %v, got %v", expectedIDs, previousResultIDs)
+			break
+		}
+	}
+}
+
+func TestMergeDiagnostics(t *testing.T) {
+	published := []protocol.Diagnostic{testDiagnostic("both"), testDiagnostic("published")}
+	pulled := []protocol.Diagnostic{testDiagnostic("both"), testDiagnostic("pulled")}
+
+	merged := mergeDiagnostics(published, pulled)
+	var messages []string
+	for _, diag := range merged {
+		messages = append(messages, diag.Message)
+	}
+	if len(messages) != 3 || messages[0] != "both" || messages[1] != "published" || messages[2] != "pulled" {
+		t.Errorf("Expected diagnostics reported by both models once, got %v", messages)
+	}
+}
+
+func TestStalePublishedDiagnostics(t *testing.T) {
+	const uri = protocol.DocumentUri("file:///test/file.go")
+
+	client := newTestClient(t, func(string, json.RawMessage) any {
+		return map[string]any{"kind": "full", "items": []protocol.Diagnostic{testDiagnostic("pulled")}}
+	})
+	client.setDiagnostics(uri, []protocol.Diagnostic{testDiagnostic("published")}, 1)
+	client.openFiles[string(uri)] = &OpenFileInfo{Version: 2, URI: uri, changed: time.Now()}
+
+	if err := client.PullDiagnostics(context.Background(), uri); err != nil {
+		t.Fatalf("PullDiagnostics failed: %v", err)
+	}
+	if got := client.GetFileDiagnostics(uri); len(got) != 1 || got[0].Message != "pulled" {
+		t.Errorf("Expected published diagnostics of an older version to be left out, got %v", got)
+	}
+
+	// Once the server publishes for the current version, both models are merged again
+	client.setDiagnostics(uri, []protocol.Diagnostic{testDiagnostic("published")}, 2)
+	if got := client.GetFileDiagnostics(uri); len(got) != 2 {
+		t.Errorf("Expected current published and pulled diagnostics, got %v", got)
+	}
+}
+
+func TestWaitForDiagnostics(t *testing.T) {
+	const uri = protocol.DocumentUri("file:///test/file.go")
+
+	client := newTestClient(t, func(string, json.RawMessage) any { return nil })
+	client.SetDiagnosticsTimeout(100 * time.Millisecond)
+	client.openFiles[string(uri)] = &OpenFileInfo{Version: 2, URI: uri, changed: time.Now()}
+
+	// Diagnostics for an older version of the file are not current
+	client.setDiagnostics(uri, []protocol.Diagnostic{testDiagnostic("old")}, 1)
+	if pending := client.WaitForDiagnostics(context.Background(), uri); len(pending) != 1 {
+		t.Errorf("Expected to time out waiting for diagnostics of the current version")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		client.setDiagnostics(uri, []protocol.Diagnostic{testDiagnostic("new")}, 2)
+	}()
+	client.SetDiagnosticsTimeout(time.Second)
+	if pending := client.WaitForDiagnostics(context.Background(), uri); len(pending) != 0 {
+		t.Errorf("Expected the diagnostics of the current version to end the wait")
+	}
+	if got := client.GetFileDiagnostics(uri); len(got) != 1 || got[0].Message != "new" {
+		t.Errorf("Expected the diagnostics of the current version, got %v", got)
+	}
+}
+
+func TestWaitForDiagnosticsAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	uri := protocol.DocumentUri("file://" + path)
+
+	client := newTestClient(t, func(string, json.RawMessage) any { return nil })
+	client.SetDiagnosticsTimeout(100 * time.Millisecond)
+	ctx := context.Background()
+
+	if err := client.OpenFile(ctx, path); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	client.setDiagnostics(uri, []protocol.Diagnostic{testDiagnostic("old")}, 1)
+
+	// Versions start over, so version 1 of the reopened file has no diagnostics yet
+	if err := client.CloseFile(ctx, path); err != nil {
+		t.Fatalf("Failed to close file: %v", err)
+	}
+	if err := client.OpenFile(ctx, path); err != nil {
+		t.Fatalf("Failed to reopen file: %v", err)
+	}
+	if pending := client.WaitForDiagnostics(ctx, uri); len(pending) != 1 {
+		t.Errorf("Expected the diagnostics from before the file was reopened not to be current")
+	}
+}
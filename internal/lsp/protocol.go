@@ -86,6 +86,11 @@ type ResponseError struct {
 	Message string `json:"message"`
 }
 
+// Error implements the error interface so callers can inspect the error code
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s (code: %d)", e.Message, e.Code)
+}
+
 func NewRequest(id any, method string, params any) (*Message, error) {
 	paramsJSON, err := json.Marshal(params)
 	if err != nil {
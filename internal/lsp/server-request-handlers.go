@@ -2,6 +2,7 @@ package lsp
 
 import (
 	"encoding/json"
+	"sync"
 
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 	"github.com/isaacphi/mcp-language-server/internal/utilities"
@@ -10,21 +11,44 @@ import (
 // FileWatchHandler is called when file watchers are registered by the server
 type FileWatchHandler func(id string, watchers []protocol.FileSystemWatcher)
 
-// fileWatchHandler holds the current file watch handler
-var fileWatchHandler FileWatchHandler
+// FileUnwatchHandler is called when file watchers are unregistered by the server
+type FileUnwatchHandler func(id string)
+
+// The current file watch and unwatch handlers
+var (
+	fileWatchHandler   FileWatchHandler
+	fileUnwatchHandler FileUnwatchHandler
+	fileWatchHandlerMu sync.RWMutex
+)
 
 // RegisterFileWatchHandler registers a handler for file watcher registrations
 func RegisterFileWatchHandler(handler FileWatchHandler) {
+	fileWatchHandlerMu.Lock()
+	defer fileWatchHandlerMu.Unlock()
 	fileWatchHandler = handler
 }
 
+// RegisterFileUnwatchHandler registers a handler for file watcher unregistrations
+func RegisterFileUnwatchHandler(handler FileUnwatchHandler) {
+	fileWatchHandlerMu.Lock()
+	defer fileWatchHandlerMu.Unlock()
+	fileUnwatchHandler = handler
+}
+
+// fileWatchHandlers returns the current file watch and unwatch handlers
+func fileWatchHandlers() (FileWatchHandler, FileUnwatchHandler) {
+	fileWatchHandlerMu.RLock()
+	defer fileWatchHandlerMu.RUnlock()
+	return fileWatchHandler, fileUnwatchHandler
+}
+
 // Requests
 
 func HandleWorkspaceConfiguration(params json.RawMessage) (any, error) {
 	return []map[string]any{{}}, nil
 }
 
-func HandleRegisterCapability(params json.RawMessage) (any, error) {
+func HandleRegisterCapability(client *Client, params json.RawMessage) (any, error) {
 	var registerParams protocol.RegistrationParams
 	if err := json.Unmarshal(params, &registerParams); err != nil {
 		lspLogger.Error("Error unmarshaling registration params: %v", err)
@@ -50,9 +74,40 @@ func HandleRegisterCapability(params json.RawMessage) (any, error) {
 				continue
 			}
 
-			// Notify file watchers
-			if fileWatchHandler != nil {
-				fileWatchHandler(reg.ID, opts.Watchers)
+			// Keep them to replay after a restart, then notify file watchers
+			stale := client.addFileWatchers(reg.ID, opts.Watchers)
+			watch, unwatch := fileWatchHandlers()
+			if unwatch != nil {
+				for _, id := range stale {
+					unwatch(id)
+				}
+			}
+			if watch != nil {
+				watch(reg.ID, opts.Watchers)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func HandleUnregisterCapability(client *Client, params json.RawMessage) (any, error) {
+	var unregisterParams protocol.UnregistrationParams
+	if err := json.Unmarshal(params, &unregisterParams); err != nil {
+		lspLogger.Error("Error unmarshaling unregistration params: %v", err)
+		return nil, err
+	}
+
+	for _, unreg := range unregisterParams.Unregisterations {
+		lspLogger.Info("Unregistration received for method: %s, id: %s", unreg.Method, unreg.ID)
+
+		if unreg.Method == "workspace/didChangeWatchedFiles" {
+			client.fileWatchersMu.Lock()
+			delete(client.fileWatchers, unreg.ID)
+			delete(client.replayedWatchers, unreg.ID)
+			client.fileWatchersMu.Unlock()
+			if _, unwatch := fileWatchHandlers(); unwatch != nil {
+				unwatch(unreg.ID)
 			}
 		}
 	}
@@ -66,8 +121,13 @@ func HandleApplyEdit(params json.RawMessage) (any, error) {
 		return protocol.ApplyWorkspaceEditResult{Applied: false}, err
 	}
 
+	description := "Edit requested by the language server"
+	if workspaceEdit.Label != "" {
+		description = workspaceEdit.Label
+	}
+
 	// Apply the edits
-	err := utilities.ApplyWorkspaceEdit(workspaceEdit.Edit)
+	err := utilities.ApplyWorkspaceEdit(workspaceEdit.Edit, description)
 	if err != nil {
 		lspLogger.Error("Error applying workspace edit: %v", err)
 		return protocol.ApplyWorkspaceEditResult{
@@ -120,9 +180,7 @@ func HandleDiagnostics(client *Client, params json.RawMessage) {
 	}
 
 	// Save diagnostics in client
-	client.diagnosticsMu.Lock()
-	client.diagnostics[diagParams.URI] = diagParams.Diagnostics
-	client.diagnosticsMu.Unlock()
+	client.setDiagnostics(diagParams.URI, diagParams.Diagnostics, diagParams.Version)
 
 	lspLogger.Info("Received diagnostics for %s: %d items", diagParams.URI, len(diagParams.Diagnostics))
 }
@@ -0,0 +1,238 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+const (
+	// Delay before the first attempt to restart a server that exited, doubled after
+	// each failed attempt up to restartMaxBackoff
+	restartInitialBackoff = 500 * time.Millisecond
+	restartMaxBackoff     = 30 * time.Second
+
+	// Attempts to restart the server before giving up. Attempts are counted again once
+	// a restarted server has been running for restartStableAfter.
+	maxRestartAttempts = 5
+	restartStableAfter = time.Minute
+
+	// Time allowed for the initialize handshake of a restarted server
+	restartInitializeTimeout = 30 * time.Second
+)
+
+// serve starts the message handling loop for the current connection. The returned
+// channel receives the error that ended it.
+func (c *Client) serve() <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- c.handleMessages()
+	}()
+	return done
+}
+
+// supervise restarts the server whenever the message handling loop ends without the
+// client being closed, until the server can't be restarted
+func (c *Client) supervise(done <-chan error) {
+	started := time.Now()
+	attempts := 0
+	backoff := restartInitialBackoff
+
+	for {
+		err := <-done
+		if c.closing.Load() {
+			return
+		}
+		c.stopServer(err)
+
+		// A server that ran for a while before exiting gets a fresh set of attempts
+		if time.Since(started) >= restartStableAfter {
+			attempts = 0
+			backoff = restartInitialBackoff
+		}
+
+		for {
+			if attempts >= maxRestartAttempts {
+				c.handlersMu.Lock()
+				c.connErr = fmt.Errorf("language server exited and could not be restarted after %d attempts: %v", attempts, err)
+				c.handlersMu.Unlock()
+				lspLogger.Error("Giving up restarting the language server after %d attempts", attempts)
+				return
+			}
+			attempts++
+
+			lspLogger.Info("Restarting language server in %v (attempt %d of %d)", backoff, attempts, maxRestartAttempts)
+			select {
+			case <-time.After(backoff):
+			case <-c.closed:
+				return
+			}
+			backoff = min(2*backoff, restartMaxBackoff)
+
+			done, err = c.restart()
+			if err == nil {
+				break
+			}
+			lspLogger.Error("Failed to restart language server: %v", err)
+			if c.closing.Load() {
+				return
+			}
+		}
+
+		started = time.Now()
+		lspLogger.Info("Language server restarted")
+	}
+}
+
+// stopServer makes sure the process of a server whose connection was lost has exited
+// and logs how it exited
+func (c *Client) stopServer(reason error) {
+	c.writeMu.Lock()
+	cmd := c.Cmd
+	c.writeMu.Unlock()
+	if cmd == nil || cmd.Process == nil {
+		lspLogger.Error("Language server connection lost: %v", reason)
+		return
+	}
+
+	// The server may have closed its output without exiting
+	if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		lspLogger.Error("Failed to kill process: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		lspLogger.Error("Language server exited: %v", err)
+	} else {
+		lspLogger.Error("Language server exited")
+	}
+}
+
+// restart starts a new server process, initializes it, runs the setup specific to the
+// server and restores the state the old one had: the open files and the file watchers
+// it registered. It returns the channel of the new message handling loop.
+func (c *Client) restart() (<-chan error, error) {
+	cmd, stdin, stdout, err := c.start()
+	if err != nil {
+		return nil, err
+	}
+
+	c.writeMu.Lock()
+	c.Cmd = cmd
+	c.stdin = stdin
+	c.stdout = bufio.NewReader(stdout)
+	c.writeMu.Unlock()
+
+	// Diagnostics of the old server are out of date, and its result IDs are unknown to
+	// the new one
+	c.clearDiagnostics()
+
+	// The new server registers its own file watchers, until then those of the old one
+	// are kept
+	c.fileWatchersMu.Lock()
+	c.replayedWatchers = make(map[string]bool, len(c.fileWatchers))
+	for id := range c.fileWatchers {
+		c.replayedWatchers[id] = true
+	}
+	c.fileWatchersMu.Unlock()
+
+	done := c.serve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), restartInitializeTimeout)
+	defer cancel()
+
+	if _, err := c.initialize(ctx); err != nil {
+		if cmd != nil && cmd.Process != nil {
+			_ = cmd.Process.Kill()
+		}
+		<-done
+		if cmd != nil {
+			_ = cmd.Wait()
+		}
+		return nil, err
+	}
+
+	c.replayOpenFiles(ctx)
+
+	c.handlersMu.Lock()
+	c.connErr = nil
+	c.handlersMu.Unlock()
+
+	// After the server is available again, as the setup and watchers may open files.
+	// Files that were reopened above are skipped.
+	if err := c.initializeServerSpecific(ctx); err != nil {
+		lspLogger.Error("Failed to set up restarted language server: %v", err)
+	}
+	c.replayFileWatchers()
+
+	return done, nil
+}
+
+// replayOpenFiles opens the files that were open in the old server in the new one, with
+// their content on disk. Files that no longer exist are forgotten.
+func (c *Client) replayOpenFiles(ctx context.Context) {
+	c.openFilesMu.Lock()
+	defer c.openFilesMu.Unlock()
+
+	for uri, fileInfo := range c.openFiles {
+		path := fileInfo.URI.Path()
+		content, err := os.ReadFile(path)
+		if err != nil {
+			lspLogger.Warn("Not reopening %s after restart: %v", path, err)
+			delete(c.openFiles, uri)
+			continue
+		}
+
+		params := protocol.DidOpenTextDocumentParams{
+			TextDocument: protocol.TextDocumentItem{
+				URI:        fileInfo.URI,
+				LanguageID: DetectLanguageID(uri),
+				Version:    fileInfo.Version,
+				Text:       string(content),
+			},
+		}
+		fileInfo.changed = time.Now()
+		if err := c.notify(ctx, "textDocument/didOpen", params); err != nil {
+			lspLogger.Error("Failed to reopen %s after restart: %v", path, err)
+		}
+	}
+
+	lspLogger.Debug("Reopened %d files after restart", len(c.openFiles))
+}
+
+// replayFileWatchers passes the file watchers registered by the old server to the file
+// watch handler again, unless the new server already registered its own
+func (c *Client) replayFileWatchers() {
+	watch, _ := fileWatchHandlers()
+	if watch == nil {
+		return
+	}
+
+	c.fileWatchersMu.Lock()
+	defer c.fileWatchersMu.Unlock()
+	for id := range c.replayedWatchers {
+		watch(id, c.fileWatchers[id])
+	}
+}
+
+// addFileWatchers records file watchers registered by the server. The first registration
+// of a restarted server replaces the watchers registered by the old one, whose IDs are
+// returned so that they can be removed.
+func (c *Client) addFileWatchers(id string, watchers []protocol.FileSystemWatcher) []string {
+	c.fileWatchersMu.Lock()
+	defer c.fileWatchersMu.Unlock()
+
+	var stale []string
+	for replayed := range c.replayedWatchers {
+		if replayed != id {
+			stale = append(stale, replayed)
+			delete(c.fileWatchers, replayed)
+		}
+	}
+	c.replayedWatchers = nil
+	c.fileWatchers[id] = watchers
+	return stale
+}
@@ -0,0 +1,196 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+func TestSupervisorRestart(t *testing.T) {
+	workspaceDir := t.TempDir()
+	path := filepath.Join(workspaceDir, "file.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// The first server never answers, the restarted one reports the files it is sent
+	stdin, stdout, crash := startTestServer(t, func(string, json.RawMessage) any { return noResponse{} })
+	reopened := make(chan protocol.TextDocumentItem, 1)
+	restarts := 0
+
+	client := newClient(stdin, stdout)
+	client.workspaceDir = workspaceDir
+	client.start = func() (*exec.Cmd, io.WriteCloser, io.Reader, error) {
+		restarts++
+		stdin, stdout, _ := startTestServer(t, func(method string, params json.RawMessage) any {
+			if method == "textDocument/didOpen" {
+				var p protocol.DidOpenTextDocumentParams
+				if err := json.Unmarshal(params, &p); err != nil {
+					t.Errorf("Failed to unmarshal params: %v", err)
+				}
+				reopened <- p.TextDocument
+			}
+			return map[string]any{}
+		})
+		return nil, stdin, stdout, nil
+	}
+	uri := protocol.DocumentUri("file://" + path)
+	client.openFiles[string(uri)] = &OpenFileInfo{Version: 3, URI: uri}
+	go client.supervise(client.serve())
+	t.Cleanup(func() { client.closing.Store(true) })
+
+	// A request in flight when the server exits fails instead of waiting forever
+	callErr := make(chan error, 1)
+	go func() {
+		callErr <- client.Call(context.Background(), "test/hang", nil, nil)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	crash()
+
+	select {
+	case err := <-callErr:
+		if err == nil || !strings.Contains(err.Error(), "exited before responding") {
+			t.Fatalf("Expected the request to fail because the server exited, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Request in flight did not fail when the server exited")
+	}
+
+	// Requests fail right away until the server is restarted
+	if err := client.Call(context.Background(), "test/echo", nil, nil); err == nil || !strings.Contains(err.Error(), "being restarted") {
+		t.Errorf("Expected requests to fail while the server is restarted, got %v", err)
+	}
+
+	select {
+	case item := <-reopened:
+		if item.URI != uri || item.Version != 3 || item.Text != "package main\n" {
+			t.Errorf("Expected the open file to be reopened with its version and content, got %+v", item)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Open files were not reopened after the restart")
+	}
+
+	// Wait for the restart to complete
+	deadline := time.Now().Add(time.Second)
+	for {
+		err := client.Call(context.Background(), "test/echo", nil, nil)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests still fail after the restart: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if restarts != 1 {
+		t.Errorf("Expected the server to be restarted once, got %d", restarts)
+	}
+}
+
+func TestSupervisorRestartTypeScript(t *testing.T) {
+	workspaceDir := t.TempDir()
+	path := filepath.Join(workspaceDir, "index.ts")
+	if err := os.WriteFile(path, []byte("export const a = 1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// The restarted server must be sent the TypeScript files of the workspace again,
+	// although none of them were open in the old one
+	stdin, stdout, crash := startTestServer(t, func(string, json.RawMessage) any { return map[string]any{} })
+	opened := make(chan protocol.TextDocumentItem, 1)
+
+	client := newClient(stdin, stdout)
+	client.workspaceDir = workspaceDir
+	client.start = func() (*exec.Cmd, io.WriteCloser, io.Reader, error) {
+		stdin, stdout, _ := startTestServer(t, func(method string, params json.RawMessage) any {
+			if method == "textDocument/didOpen" {
+				var p protocol.DidOpenTextDocumentParams
+				if err := json.Unmarshal(params, &p); err != nil {
+					t.Errorf("Failed to unmarshal params: %v", err)
+				}
+				opened <- p.TextDocument
+			}
+			return map[string]any{}
+		})
+		return &exec.Cmd{Path: "/usr/bin/typescript-language-server"}, stdin, stdout, nil
+	}
+	go client.supervise(client.serve())
+	t.Cleanup(func() { client.closing.Store(true) })
+
+	crash()
+
+	select {
+	case item := <-opened:
+		if item.URI != protocol.DocumentUri("file://"+path) {
+			t.Errorf("Expected %s to be opened, got %s", path, item.URI)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TypeScript files were not opened after the restart")
+	}
+
+	// Wait for the restart to complete
+	deadline := time.Now().Add(time.Second)
+	for client.Call(context.Background(), "test/echo", nil, nil) != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests still fail after the restart")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFileWatcherRegistrations(t *testing.T) {
+	var watched, unwatched []string
+	RegisterFileWatchHandler(func(id string, _ []protocol.FileSystemWatcher) { watched = append(watched, id) })
+	RegisterFileUnwatchHandler(func(id string) { unwatched = append(unwatched, id) })
+	t.Cleanup(func() {
+		RegisterFileWatchHandler(nil)
+		RegisterFileUnwatchHandler(nil)
+	})
+
+	register := func(client *Client, id string) {
+		params, _ := json.Marshal(protocol.RegistrationParams{Registrations: []protocol.Registration{{
+			ID:              id,
+			Method:          "workspace/didChangeWatchedFiles",
+			RegisterOptions: protocol.DidChangeWatchedFilesRegistrationOptions{Watchers: []protocol.FileSystemWatcher{}},
+		}}})
+		if _, err := HandleRegisterCapability(client, params); err != nil {
+			t.Fatalf("Failed to register: %v", err)
+		}
+	}
+
+	client := newClient(nil, nil)
+	register(client, "old-1")
+	register(client, "old-2")
+
+	// After a restart the old watchers are replayed until the new server registers its own
+	client.replayedWatchers = map[string]bool{"old-1": true, "old-2": true}
+	watched = nil
+	client.replayFileWatchers()
+	if len(watched) != 2 {
+		t.Errorf("Expected the old watchers to be replayed, got %v", watched)
+	}
+	register(client, "new")
+	if len(unwatched) != 2 || len(client.fileWatchers) != 1 || client.fileWatchers["new"] == nil {
+		t.Errorf("Expected the old watchers to be replaced, unwatched %v, registered %v", unwatched, client.fileWatchers)
+	}
+
+	unwatched = nil
+	params, _ := json.Marshal(protocol.UnregistrationParams{Unregisterations: []protocol.Unregistration{{
+		ID:     "new",
+		Method: "workspace/didChangeWatchedFiles",
+	}}})
+	if _, err := HandleUnregisterCapability(client, params); err != nil {
+		t.Fatalf("Failed to unregister: %v", err)
+	}
+	if len(unwatched) != 1 || unwatched[0] != "new" || len(client.fileWatchers) != 0 {
+		t.Errorf("Expected the watchers to be unregistered, unwatched %v, registered %v", unwatched, client.fileWatchers)
+	}
+}
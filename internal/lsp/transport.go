@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/isaacphi/mcp-language-server/internal/logging"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
 // Create component-specific loggers
@@ -94,10 +97,12 @@ func ReadMessage(r *bufio.Reader) (*Message, error) {
 	return &msg, nil
 }
 
-// handleMessages reads and dispatches messages in a loop
-func (c *Client) handleMessages() {
+// handleMessages reads and dispatches messages in a loop until the connection to the
+// server is lost. Requests still waiting for a response then fail.
+func (c *Client) handleMessages() error {
+	stdout := c.stdout
 	for {
-		msg, err := ReadMessage(c.stdout)
+		msg, err := ReadMessage(stdout)
 		if err != nil {
 			// Check if this is due to normal shutdown (EOF when closing connection)
 			if strings.Contains(err.Error(), "EOF") {
@@ -105,7 +110,8 @@ func (c *Client) handleMessages() {
 			} else {
 				lspLogger.Error("Error reading message: %v", err)
 			}
-			return
+			c.connectionLost(err)
+			return err
 		}
 
 		// Handle server->client request (has both Method and ID)
@@ -150,7 +156,7 @@ func (c *Client) handleMessages() {
 			}
 
 			// Send response back to server
-			if err := WriteMessage(c.stdin, response); err != nil {
+			if err := c.write(response); err != nil {
 				lspLogger.Error("Error sending response to server: %v", err)
 			}
 
@@ -176,14 +182,14 @@ func (c *Client) handleMessages() {
 		if msg.ID != nil && msg.ID.Value != nil && msg.Method == "" {
 			// Convert ID to string for map lookup
 			idStr := msg.ID.String()
-			c.handlersMu.RLock()
+			c.handlersMu.Lock()
 			ch, ok := c.handlers[idStr]
-			c.handlersMu.RUnlock()
+			delete(c.handlers, idStr)
+			c.handlersMu.Unlock()
 
 			if ok {
 				lspLogger.Debug("Sending response for ID %v to handler", msg.ID)
 				ch <- msg
-				close(ch)
 			} else {
 				lspLogger.Debug("No handler for response ID: %v", msg.ID)
 			}
@@ -191,8 +197,46 @@ func (c *Client) handleMessages() {
 	}
 }
 
-// Call makes a request and waits for the response
+// DefaultRequestTimeout is how long to wait for the response to a request whose context
+// has no earlier deadline
+const DefaultRequestTimeout = time.Minute
+
+// methodTimeouts are shorter timeouts for requests that servers should answer quickly,
+// used when they are below the client's request timeout
+var methodTimeouts = map[string]time.Duration{
+	"shutdown":                       5 * time.Second,
+	"textDocument/completion":        15 * time.Second,
+	"textDocument/documentHighlight": 15 * time.Second,
+	"textDocument/hover":             15 * time.Second,
+	"textDocument/inlayHint":         15 * time.Second,
+	"textDocument/signatureHelp":     15 * time.Second,
+	"workspace/symbol":               30 * time.Second,
+}
+
+// SetRequestTimeout sets how long to wait for the response to a request, for methods
+// without a shorter timeout of their own
+func (c *Client) SetRequestTimeout(timeout time.Duration) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+	c.requestTimeout = timeout
+}
+
+// Call makes a request and waits for the response, until the context is done or the
+// timeout of the method expires. The server is then told to cancel the request. Call
+// fails right away if the server exited and is being restarted.
 func (c *Client) Call(ctx context.Context, method string, params any, result any) error {
+	if method == "shutdown" {
+		c.closing.Store(true)
+	}
+	return c.request(ctx, method, params, result, true)
+}
+
+// call makes a request even while the server is restarted, for the supervisor
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	return c.request(ctx, method, params, result, false)
+}
+
+func (c *Client) request(ctx context.Context, method string, params any, result any, checkAvailable bool) error {
 	id := c.nextID.Add(1)
 
 	lspLogger.Debug("Making call: method=%s id=%v", method, id)
@@ -207,9 +251,23 @@ func (c *Client) Call(ctx context.Context, method string, params any, result any
 	// Convert ID to string for map lookup
 	idStr := msg.ID.String()
 	c.handlersMu.Lock()
+	// Checked together with registering the handler, so that the request either fails
+	// here or is failed by connectionLost
+	if checkAvailable && c.connErr != nil {
+		err := c.connErr
+		c.handlersMu.Unlock()
+		return err
+	}
 	c.handlers[idStr] = ch
+	timeout := c.requestTimeout
 	c.handlersMu.Unlock()
 
+	if methodTimeout, ok := methodTimeouts[method]; ok && methodTimeout < timeout {
+		timeout = methodTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	defer func() {
 		c.handlersMu.Lock()
 		delete(c.handlers, idStr)
@@ -217,20 +275,33 @@ func (c *Client) Call(ctx context.Context, method string, params any, result any
 	}()
 
 	// Send request
-	if err := WriteMessage(c.stdin, msg); err != nil {
+	if err := c.write(msg); err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 
 	lspLogger.Debug("Waiting for response to request ID: %v", msg.ID)
 
 	// Wait for response
-	resp := <-ch
+	var resp *Message
+	select {
+	case resp = <-ch:
+	case <-ctx.Done():
+		// The handler is removed on return, a late response is dropped
+		c.cancelRequest(msg.ID)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s timed out: %w", method, ctx.Err())
+		}
+		return fmt.Errorf("%s cancelled: %w", method, ctx.Err())
+	}
+	if resp == nil {
+		return fmt.Errorf("%s failed: language server exited before responding", method)
+	}
 
 	lspLogger.Debug("Received response for request ID: %v", msg.ID)
 
 	if resp.Error != nil {
 		lspLogger.Error("Request failed: %s (code: %d)", resp.Error.Message, resp.Error.Code)
-		return fmt.Errorf("request failed: %s (code: %d)", resp.Error.Message, resp.Error.Code)
+		return fmt.Errorf("request failed: %w", resp.Error)
 	}
 
 	if result != nil {
@@ -249,8 +320,25 @@ func (c *Client) Call(ctx context.Context, method string, params any, result any
 	return nil
 }
 
-// Notify sends a notification (a request without an ID that doesn't expect a response)
+// Notify sends a notification (a request without an ID that doesn't expect a response).
+// It fails right away if the server exited and is being restarted.
 func (c *Client) Notify(ctx context.Context, method string, params any) error {
+	if method == "exit" {
+		c.closing.Store(true)
+	}
+
+	c.handlersMu.RLock()
+	err := c.connErr
+	c.handlersMu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return c.notify(ctx, method, params)
+}
+
+// notify sends a notification even while the server is restarted, for the supervisor
+func (c *Client) notify(ctx context.Context, method string, params any) error {
 	lspLogger.Debug("Sending notification: method=%s", method)
 
 	msg, err := NewNotification(method, params)
@@ -258,12 +346,45 @@ func (c *Client) Notify(ctx context.Context, method string, params any) error {
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
 
-	if err := WriteMessage(c.stdin, msg); err != nil {
+	if err := c.write(msg); err != nil {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
 	return nil
 }
 
+// cancelRequest tells the server that the response to a request is no longer needed
+func (c *Client) cancelRequest(id *MessageID) {
+	lspLogger.Debug("Cancelling request ID: %v", id)
+	if err := c.notify(context.Background(), "$/cancelRequest", protocol.CancelParams{ID: id.Value}); err != nil {
+		lspLogger.Debug("Failed to cancel request ID %v: %v", id, err)
+	}
+}
+
+// write sends a message to the server. Messages are written one at a time so that
+// concurrent requests don't interleave.
+func (c *Client) write(msg *Message) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return WriteMessage(c.stdin, msg)
+}
+
+// connectionLost fails the requests waiting for a response from a server that is no
+// longer connected. Unless the client is closing, new requests fail too until the
+// supervisor has restarted the server.
+func (c *Client) connectionLost(err error) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	if !c.closing.Load() && c.connErr == nil {
+		c.connErr = fmt.Errorf("language server exited (%v) and is being restarted, try again shortly", err)
+	}
+
+	for id, ch := range c.handlers {
+		ch <- nil
+		delete(c.handlers, id)
+	}
+}
+
 type NotificationHandler func(params json.RawMessage)
 type ServerRequestHandler func(params json.RawMessage) (any, error)
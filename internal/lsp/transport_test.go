@@ -0,0 +1,89 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+func TestCallCancellation(t *testing.T) {
+	cancelled := make(chan any, 2)
+	client := newTestClient(t, func(method string, params json.RawMessage) any {
+		if method == "$/cancelRequest" {
+			var p protocol.CancelParams
+			if err := json.Unmarshal(params, &p); err != nil {
+				t.Errorf("Failed to unmarshal params: %v", err)
+			}
+			cancelled <- p.ID
+		}
+		return noResponse{}
+	})
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		cancel    bool
+		expectErr string
+	}{
+		{
+			name:      "Cancelled context",
+			timeout:   DefaultRequestTimeout,
+			cancel:    true,
+			expectErr: "test/hang cancelled",
+		},
+		{
+			name:      "Request timeout",
+			timeout:   50 * time.Millisecond,
+			expectErr: "test/hang timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.SetRequestTimeout(tt.timeout)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				go func() {
+					time.Sleep(50 * time.Millisecond)
+					cancel()
+				}()
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- client.Call(ctx, "test/hang", nil, nil)
+			}()
+
+			select {
+			case err := <-done:
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("Call did not return")
+			}
+
+			select {
+			case id := <-cancelled:
+				// IDs are numbers on the wire
+				if _, ok := id.(float64); !ok {
+					t.Errorf("Expected the request ID in $/cancelRequest, got %v", id)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("The server was not told to cancel the request")
+			}
+
+			client.handlersMu.RLock()
+			pending := len(client.handlers)
+			client.handlersMu.RUnlock()
+			if pending != 0 {
+				t.Errorf("Expected the response handler to be removed, %d remain", pending)
+			}
+		})
+	}
+}
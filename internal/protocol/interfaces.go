@@ -115,3 +115,65 @@ func (e Or_TextDocumentEdit_edits_Elem) AsTextEdit() (TextEdit, error) {
 		return TextEdit{}, fmt.Errorf("unknown text edit type: %T", e.Value)
 	}
 }
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_definition) Locations() ([]Location, error) {
+	return definitionLocations(r.Value)
+}
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_declaration) Locations() ([]Location, error) {
+	return definitionLocations(r.Value)
+}
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_implementation) Locations() ([]Location, error) {
+	return definitionLocations(r.Value)
+}
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_typeDefinition) Locations() ([]Location, error) {
+	return definitionLocations(r.Value)
+}
+
+// definitionLocations flattens the Location, []Location and []LocationLink variants
+// returned by definition-like requests. Links point at their target selection range.
+func definitionLocations(value any) ([]Location, error) {
+	switch v := value.(type) {
+	case nil:
+		return make([]Location, 0), nil
+	case Or_Definition:
+		return definitionLocations(v.Value)
+	case Or_Declaration:
+		return definitionLocations(v.Value)
+	case Location:
+		return []Location{v}, nil
+	case []Location:
+		return v, nil
+	case []LocationLink:
+		locations := make([]Location, len(v))
+		for i, link := range v {
+			locations[i] = Location{
+				URI:   link.TargetURI,
+				Range: link.TargetSelectionRange,
+			}
+		}
+		return locations, nil
+	default:
+		return nil, fmt.Errorf("unknown location type: %T", value)
+	}
+}
+
+// Items converts the Value to a slice of CompletionItem, reporting whether the list is incomplete
+func (r Or_Result_textDocument_completion) Items() ([]CompletionItem, bool, error) {
+	switch v := r.Value.(type) {
+	case nil:
+		return nil, false, nil
+	case CompletionList:
+		return v.Items, v.IsIncomplete, nil
+	case []CompletionItem:
+		return v, false, nil
+	default:
+		return nil, false, fmt.Errorf("unknown completion result type: %T", r.Value)
+	}
+}
@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The protocol encodes tuples as JSON arrays, while the generated types for them are
+// structs with a field per element
+
+// MarshalJSON encodes the label offsets as a [start, end] array
+func (t Tuple_ParameterInformation_label_Item1) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]uint32{t.Fld0, t.Fld1})
+}
+
+// UnmarshalJSON decodes label offsets from a [start, end] array
+func (t *Tuple_ParameterInformation_label_Item1) UnmarshalJSON(data []byte) error {
+	var offsets []uint32
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return err
+	}
+	if len(offsets) != 2 {
+		return fmt.Errorf("expected 2 label offsets, got %d", len(offsets))
+	}
+	t.Fld0, t.Fld1 = offsets[0], offsets[1]
+	return nil
+}
@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetIncomingCalls returns the tree of functions that call the given symbol, up to depth levels deep.
+// The symbol is identified either by name or, if symbolName is empty, by its position in a file.
+func GetIncomingCalls(ctx context.Context, client *lsp.Client, symbolName, filePath string, line, column, depth int) (string, error) {
+	return getCallHierarchy(ctx, client, symbolName, filePath, line, column, depth, false)
+}
+
+// GetOutgoingCalls returns the tree of functions called by the given symbol, up to depth levels deep.
+// The symbol is identified either by name or, if symbolName is empty, by its position in a file.
+func GetOutgoingCalls(ctx context.Context, client *lsp.Client, symbolName, filePath string, line, column, depth int) (string, error) {
+	return getCallHierarchy(ctx, client, symbolName, filePath, line, column, depth, true)
+}
+
+func getCallHierarchy(ctx context.Context, client *lsp.Client, symbolName, filePath string, line, column, depth int, outgoing bool) (string, error) {
+	if depth < 1 {
+		depth = 1
+	}
+
+	positions, err := resolvePositions(ctx, client, symbolName, filePath, line, column)
+	if err != nil {
+		return "", err
+	}
+
+	var items []protocol.CallHierarchyItem
+	for _, pos := range positions {
+		prepared, err := client.PrepareCallHierarchy(ctx, protocol.CallHierarchyPrepareParams{
+			TextDocumentPositionParams: pos,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to prepare call hierarchy: %v", err)
+		}
+		items = append(items, prepared...)
+	}
+
+	if len(items) == 0 {
+		if symbolName != "" {
+			return fmt.Sprintf("%s not found", symbolName), nil
+		}
+		return fmt.Sprintf("No callable symbol found at %s L%d:C%d", filePath, line, column), nil
+	}
+
+	direction := "Incoming calls to"
+	if outgoing {
+		direction = "Outgoing calls from"
+	}
+
+	walker := &callHierarchyWalker{
+		client:   client,
+		outgoing: outgoing,
+		maxDepth: depth,
+		visited:  make(map[string]bool),
+		files:    make(map[string][]string),
+	}
+
+	var result strings.Builder
+	for _, item := range items {
+		result.WriteString("---\n\n")
+		result.WriteString(fmt.Sprintf("%s: %s\n", direction, item.Name))
+		result.WriteString(fmt.Sprintf("Kind: %s\n", protocol.TableKindMap[item.Kind]))
+		if item.Detail != "" {
+			result.WriteString(fmt.Sprintf("Detail: %s\n", item.Detail))
+		}
+		result.WriteString(fmt.Sprintf("File: %s\n", strings.TrimPrefix(string(item.URI), "file://")))
+		result.WriteString(fmt.Sprintf("Range: %s\n\n", formatRange(item.Range)))
+
+		count, err := walker.walk(ctx, &result, item, 1)
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			if outgoing {
+				result.WriteString("No outgoing calls found\n")
+			} else {
+				result.WriteString("No incoming calls found\n")
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
+// callHierarchyWalker recursively expands call hierarchy items into an indented tree
+type callHierarchyWalker struct {
+	client   *lsp.Client
+	outgoing bool
+	maxDepth int
+	// Items on the current path, used to stop at recursive calls
+	visited map[string]bool
+	// Cache of file contents used for call site snippets
+	files map[string][]string
+}
+
+type callHierarchyCall struct {
+	item protocol.CallHierarchyItem
+	// URI of the file the call ranges are in
+	uri    protocol.DocumentUri
+	ranges []protocol.Range
+}
+
+func (w *callHierarchyWalker) walk(ctx context.Context, out *strings.Builder, item protocol.CallHierarchyItem, level int) (int, error) {
+	key := callHierarchyKey(item)
+	w.visited[key] = true
+	defer delete(w.visited, key)
+
+	calls, err := w.calls(ctx, item)
+	if err != nil {
+		// Only fail the whole request if the root item cannot be expanded
+		if level == 1 {
+			return 0, err
+		}
+		toolsLogger.Error("Failed to expand call hierarchy for %s: %v", item.Name, err)
+		out.WriteString(fmt.Sprintf("%s(error: %v)\n", strings.Repeat("  ", level-1), err))
+		return 0, nil
+	}
+
+	indent := strings.Repeat("  ", level-1)
+	for _, call := range calls {
+		out.WriteString(fmt.Sprintf("%s- %s (%s)", indent, call.item.Name, protocol.TableKindMap[call.item.Kind]))
+
+		recursive := w.visited[callHierarchyKey(call.item)]
+		if recursive {
+			out.WriteString(" (recursive)")
+		}
+		out.WriteString("\n")
+
+		out.WriteString(fmt.Sprintf("%s  %s %s\n",
+			indent,
+			strings.TrimPrefix(string(call.item.URI), "file://"),
+			formatRange(call.item.Range),
+		))
+		for _, rng := range call.ranges {
+			out.WriteString(fmt.Sprintf("%s    %s\n", indent, w.snippet(call.uri, rng)))
+		}
+
+		if !recursive && level < w.maxDepth {
+			if _, err := w.walk(ctx, out, call.item, level+1); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return len(calls), nil
+}
+
+func (w *callHierarchyWalker) calls(ctx context.Context, item protocol.CallHierarchyItem) ([]callHierarchyCall, error) {
+	var calls []callHierarchyCall
+
+	if w.outgoing {
+		outgoing, err := w.client.OutgoingCalls(ctx, protocol.CallHierarchyOutgoingCallsParams{Item: item})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get outgoing calls: %v", err)
+		}
+		// Outgoing call ranges are relative to the caller, which is the item being expanded
+		for _, call := range outgoing {
+			calls = append(calls, callHierarchyCall{item: call.To, uri: item.URI, ranges: call.FromRanges})
+		}
+		return calls, nil
+	}
+
+	incoming, err := w.client.IncomingCalls(ctx, protocol.CallHierarchyIncomingCallsParams{Item: item})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incoming calls: %v", err)
+	}
+	for _, call := range incoming {
+		calls = append(calls, callHierarchyCall{item: call.From, uri: call.From.URI, ranges: call.FromRanges})
+	}
+	return calls, nil
+}
+
+// snippet formats the line containing a call site
+func (w *callHierarchyWalker) snippet(uri protocol.DocumentUri, rng protocol.Range) string {
+	path := strings.TrimPrefix(string(uri), "file://")
+	lines, ok := w.files[path]
+	if !ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			toolsLogger.Error("Error reading file: %v", err)
+		}
+		lines = strings.Split(string(content), "\n")
+		w.files[path] = lines
+	}
+
+	location := fmt.Sprintf("L%d:C%d", rng.Start.Line+1, rng.Start.Character+1)
+	if int(rng.Start.Line) >= len(lines) {
+		return location
+	}
+	return fmt.Sprintf("%s: %s", location, strings.TrimSpace(lines[rng.Start.Line]))
+}
+
+func callHierarchyKey(item protocol.CallHierarchyItem) string {
+	return fmt.Sprintf("%s:%d:%d", item.URI, item.SelectionRange.Start.Line, item.SelectionRange.Start.Character)
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// ListChanges lists the most recent change sets recorded in the undo journal with the
+// files each of them touched
+func ListChanges(limit int) (string, error) {
+	journal := utilities.GetJournal()
+	if journal == nil {
+		return "", fmt.Errorf("the undo journal is not enabled")
+	}
+
+	changes := journal.Changes()
+	if len(changes) == 0 {
+		return "No changes recorded", nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Recorded changes: %d", len(changes)))
+	if limit > 0 && len(changes) > limit {
+		output.WriteString(fmt.Sprintf(" (showing last %d)", limit))
+		changes = changes[:limit]
+	}
+	output.WriteString("\n")
+
+	for _, change := range changes {
+		output.WriteString(fmt.Sprintf("\nChange %d at %s", change.ID, change.Time.Format("2006-01-02 15:04:05")))
+		if change.Undone {
+			output.WriteString(" [undone]")
+		}
+		output.WriteString("\n" + change.Description + "\n")
+		for _, file := range change.Files {
+			output.WriteString(fmt.Sprintf("  %s (%s)\n", file.Path, fileChangeKind(file)))
+		}
+	}
+
+	return output.String(), nil
+}
+
+// UndoChange restores the files of a change set to their state before the change. Unless
+// force is set, it refuses if any of them were modified since.
+func UndoChange(id int, force bool) (string, error) {
+	journal := utilities.GetJournal()
+	if journal == nil {
+		return "", fmt.Errorf("the undo journal is not enabled")
+	}
+
+	undo, err := journal.Undo(id, force)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Undid change %d\n", id))
+	if undo == nil {
+		// The files were restored but the undo itself couldn't be recorded
+		return output.String(), nil
+	}
+	output.WriteString(fmt.Sprintf("Restored %d files:\n", len(undo.Files)))
+	for _, file := range undo.Files {
+		output.WriteString(fmt.Sprintf("%s (%s)\n", file.Path, fileChangeKind(file)))
+	}
+	output.WriteString(fmt.Sprintf("The undo was recorded as change %d, which can be undone in turn.", undo.ID))
+
+	return output.String(), nil
+}
+
+func fileChangeKind(file utilities.FileChange) string {
+	switch {
+	case file.IsDir:
+		return "directory"
+	case file.Existed && file.AfterHash != "":
+		return "modified"
+	case file.Existed:
+		return "deleted"
+	case file.AfterHash != "":
+		return "created"
+	default:
+		return "unchanged"
+	}
+}
@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// CodeActionRange identifies the lines code actions are requested for and, optionally,
+// the diagnostic they should fix. Lines are 1-indexed and inclusive.
+type CodeActionRange struct {
+	FilePath  string
+	StartLine int
+	EndLine   int
+	// If set, only the diagnostic in the range whose message contains this text is used
+	Diagnostic string
+	// If set, only actions of these kinds (e.g. "quickfix", "refactor.extract") are returned
+	Kinds []string
+}
+
+// GetCodeActions lists the quick fixes, refactorings and source actions available for a range
+func GetCodeActions(ctx context.Context, client *lsp.Client, actionRange CodeActionRange) (string, error) {
+	actions, params, err := requestCodeActions(ctx, client, actionRange)
+	if err != nil {
+		return "", err
+	}
+
+	if len(actions) == 0 {
+		return fmt.Sprintf("%s\nNo code actions available for L%d-L%d", actionRange.FilePath, actionRange.StartLine, actionRange.EndLine), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s\nCode actions for L%d-L%d: %d\n\n", actionRange.FilePath, actionRange.StartLine, actionRange.EndLine, len(actions)))
+
+	for i, action := range actions {
+		output.WriteString(fmt.Sprintf("[%d] %s", i+1, action.Title))
+		if action.Kind != "" {
+			output.WriteString(fmt.Sprintf(" (%s)", action.Kind))
+		}
+		if action.IsPreferred {
+			output.WriteString(" [preferred]")
+		}
+		output.WriteString("\n")
+
+		if action.Disabled != nil {
+			output.WriteString(fmt.Sprintf("    Disabled: %s\n", action.Disabled.Reason))
+		}
+		for _, diag := range action.Diagnostics {
+			output.WriteString(fmt.Sprintf("    Fixes: L%d:C%d: %s\n", diag.Range.Start.Line+1, diag.Range.Start.Character+1, diag.Message))
+		}
+	}
+
+	if len(params.Context.Diagnostics) > 0 {
+		output.WriteString(fmt.Sprintf("\nDiagnostics in range: %d\n", len(params.Context.Diagnostics)))
+	}
+
+	return output.String(), nil
+}
+
+// ApplyCodeAction applies the code action at the given 1-based index of the list returned
+// by GetCodeActions for the same range. The action is resolved first if needed, then its
+// workspace edit is applied and its command, if any, is executed.
+func ApplyCodeAction(ctx context.Context, client *lsp.Client, actionRange CodeActionRange, index int) (string, error) {
+	actions, _, err := requestCodeActions(ctx, client, actionRange)
+	if err != nil {
+		return "", err
+	}
+
+	if len(actions) == 0 {
+		return "", fmt.Errorf("no code actions available for %s L%d-L%d", actionRange.FilePath, actionRange.StartLine, actionRange.EndLine)
+	}
+
+	if index < 1 || index > len(actions) {
+		return "", fmt.Errorf("invalid code action index: %d. Available range: 1-%d", index, len(actions))
+	}
+
+	action := actions[index-1]
+	if action.Disabled != nil {
+		return "", fmt.Errorf("code action %q is disabled: %s", action.Title, action.Disabled.Reason)
+	}
+
+	return applyCodeAction(ctx, client, action)
+}
+
+// applyCodeAction resolves a code action if needed, applies its edit and executes its command
+func applyCodeAction(ctx context.Context, client *lsp.Client, action protocol.CodeAction) (string, error) {
+	// Servers may leave out the edit until the action is resolved
+	if action.Edit == nil && action.Data != nil {
+		resolved, err := client.ResolveCodeAction(ctx, action)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve code action: %v", err)
+		}
+		action = resolved
+	}
+
+	if action.Edit == nil && action.Command == nil {
+		return "", fmt.Errorf("code action %q has no edit or command", action.Title)
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Applied code action: %s\n", action.Title))
+
+	if action.Edit != nil {
+		if err := utilities.ApplyWorkspaceEdit(*action.Edit, "Code action: "+action.Title); err != nil {
+			return "", fmt.Errorf("failed to apply changes: %v", err)
+		}
+		output.WriteString(summarizeWorkspaceEdit(*action.Edit))
+	}
+
+	// The command runs after the edit, as required by the specification. Any edits it
+	// makes are sent back by the server as workspace/applyEdit requests.
+	if action.Command != nil {
+		_, err := client.ExecuteCommand(ctx, protocol.ExecuteCommandParams{
+			Command:   action.Command.Command,
+			Arguments: action.Command.Arguments,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to execute code action command: %v", err)
+		}
+		output.WriteString(fmt.Sprintf("Executed command: %s\n", action.Command.Command))
+	}
+
+	return output.String(), nil
+}
+
+// requestCodeActions requests the code actions for a range, using the cached diagnostics
+// in the range as context. Bare commands are returned as code actions with only a command.
+func requestCodeActions(ctx context.Context, client *lsp.Client, actionRange CodeActionRange) ([]protocol.CodeAction, protocol.CodeActionParams, error) {
+	filePath := actionRange.FilePath
+	if actionRange.StartLine < 1 {
+		return nil, protocol.CodeActionParams{}, fmt.Errorf("startLine must be at least 1")
+	}
+	if actionRange.EndLine < actionRange.StartLine {
+		return nil, protocol.CodeActionParams{}, fmt.Errorf("endLine must not be before startLine")
+	}
+
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return nil, protocol.CodeActionParams{}, fmt.Errorf("could not open file: %v", err)
+	}
+
+	// Quick fixes are offered for the diagnostics in the range
+	uri := protocol.DocumentUri("file://" + filePath)
+	client.WaitForDiagnostics(ctx, uri)
+	rng := protocol.Range{
+		Start: protocol.Position{Line: uint32(actionRange.StartLine - 1)},
+		// End at the start of the line after the range so the whole last line is included
+		End: protocol.Position{Line: uint32(actionRange.EndLine)},
+	}
+
+	var diagnostics []protocol.Diagnostic
+	for _, diag := range client.GetFileDiagnostics(uri) {
+		if diag.Range.Start.Line > rng.End.Line || diag.Range.End.Line < rng.Start.Line {
+			continue
+		}
+		if actionRange.Diagnostic != "" && !strings.Contains(diag.Message, actionRange.Diagnostic) {
+			continue
+		}
+		diagnostics = append(diagnostics, diag)
+	}
+
+	if actionRange.Diagnostic != "" {
+		switch len(diagnostics) {
+		case 0:
+			return nil, protocol.CodeActionParams{}, fmt.Errorf("no diagnostic matching %q found in L%d-L%d", actionRange.Diagnostic, actionRange.StartLine, actionRange.EndLine)
+		case 1:
+			// Ask for actions at the diagnostic itself, as servers usually only offer
+			// fixes whose range overlaps the request
+			rng = diagnostics[0].Range
+		default:
+			return nil, protocol.CodeActionParams{}, fmt.Errorf("%d diagnostics matching %q found in L%d-L%d, use a narrower range or more specific text", len(diagnostics), actionRange.Diagnostic, actionRange.StartLine, actionRange.EndLine)
+		}
+	}
+
+	if diagnostics == nil {
+		diagnostics = []protocol.Diagnostic{}
+	}
+
+	var only []protocol.CodeActionKind
+	for _, kind := range actionRange.Kinds {
+		only = append(only, protocol.CodeActionKind(kind))
+	}
+
+	params := protocol.CodeActionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Range:        rng,
+		Context: protocol.CodeActionContext{
+			Diagnostics: diagnostics,
+			Only:        only,
+		},
+	}
+
+	result, err := client.CodeAction(ctx, params)
+	if err != nil {
+		return nil, params, fmt.Errorf("failed to get code actions: %v", err)
+	}
+
+	actions := make([]protocol.CodeAction, 0, len(result))
+	for _, item := range result {
+		switch v := item.Value.(type) {
+		case protocol.CodeAction:
+			actions = append(actions, v)
+		case protocol.Command:
+			cmd := v
+			actions = append(actions, protocol.CodeAction{Title: v.Title, Command: &cmd})
+		}
+	}
+
+	return actions, params, nil
+}
+
+// summarizeWorkspaceEdit lists the files changed by a workspace edit and the number of edits in each
+func summarizeWorkspaceEdit(edit protocol.WorkspaceEdit) string {
+	var lines []string
+	for uri, edits := range edit.Changes {
+		lines = append(lines, fmt.Sprintf("%s: %d edits", strings.TrimPrefix(string(uri), "file://"), len(edits)))
+	}
+	for _, change := range edit.DocumentChanges {
+		switch {
+		case change.TextDocumentEdit != nil:
+			lines = append(lines, fmt.Sprintf("%s: %d edits",
+				strings.TrimPrefix(string(change.TextDocumentEdit.TextDocument.URI), "file://"),
+				len(change.TextDocumentEdit.Edits)))
+		case change.CreateFile != nil:
+			lines = append(lines, fmt.Sprintf("%s: created", strings.TrimPrefix(string(change.CreateFile.URI), "file://")))
+		case change.RenameFile != nil:
+			lines = append(lines, fmt.Sprintf("%s: renamed to %s",
+				strings.TrimPrefix(string(change.RenameFile.OldURI), "file://"),
+				strings.TrimPrefix(string(change.RenameFile.NewURI), "file://")))
+		case change.DeleteFile != nil:
+			lines = append(lines, fmt.Sprintf("%s: deleted", strings.TrimPrefix(string(change.DeleteFile.URI), "file://")))
+		}
+	}
+	sort.Strings(lines)
+
+	if len(lines) == 0 {
+		return "No files changed\n"
+	}
+	return fmt.Sprintf("Changed %d files:\n%s\n", len(lines), strings.Join(lines, "\n"))
+}
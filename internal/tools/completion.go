@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// maxResolvedCompletions is the number of completion items that are resolved for their
+// documentation, each of which is a round trip to the server
+const maxResolvedCompletions = 20
+
+// GetCompletions returns the top completion items at a position, in the order ranked by the
+// language server, with their kind, detail and documentation. If text is not empty, it is
+// inserted at the position first, without saving the file, and completions are requested
+// after it, e.g. "fmt." to list the members of a package. Only the first items are resolved
+// for documentation the server didn't include.
+func GetCompletions(ctx context.Context, client *lsp.Client, filePath string, line, column int, text string, limit int) (string, error) {
+	var items []protocol.CompletionItem
+	var incomplete bool
+	total := 0
+	err := withUnsavedText(ctx, client, filePath, line, column, text, func(pos protocol.TextDocumentPositionParams) error {
+		result, err := client.Completion(ctx, protocol.CompletionParams{
+			TextDocumentPositionParams: pos,
+			Context: protocol.CompletionContext{
+				TriggerKind: protocol.Invoked,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get completions: %v", err)
+		}
+
+		items, incomplete, err = result.Items()
+		if err != nil {
+			return fmt.Errorf("failed to process completions: %v", err)
+		}
+
+		// Clients are expected to order items by sortText, falling back to the label
+		sort.SliceStable(items, func(i, j int) bool {
+			return completionSortKey(items[i]) < completionSortKey(items[j])
+		})
+		total = len(items)
+		if limit > 0 && len(items) > limit {
+			items = items[:limit]
+		}
+
+		// Documentation is often only sent when an item is resolved, which must happen
+		// while the server still sees the unsaved text
+		if !client.SupportsCompletionResolve() {
+			return nil
+		}
+		for i := range items {
+			if i == maxResolvedCompletions {
+				break
+			}
+			if items[i].Documentation != nil && items[i].Detail != "" {
+				continue
+			}
+			resolved, err := client.ResolveCompletionItem(ctx, items[i])
+			if err != nil {
+				toolsLogger.Debug("Failed to resolve completion item %s: %v", items[i].Label, err)
+				continue
+			}
+			items[i] = resolved
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(items) == 0 {
+		return fmt.Sprintf("%s\nNo completions available at L%d:C%d", filePath, line, column), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s\nCompletions at L%d:C%d: %d", filePath, line, column, total))
+	if len(items) < total {
+		output.WriteString(fmt.Sprintf(" (showing first %d)", len(items)))
+	}
+	if incomplete {
+		output.WriteString(" (incomplete, type more text to narrow down)")
+	}
+	output.WriteString("\n\n")
+
+	for i, item := range items {
+		output.WriteString(fmt.Sprintf("%d. %s", i+1, item.Label))
+		if kind, ok := protocol.TableCompletionKindMap[item.Kind]; ok {
+			output.WriteString(fmt.Sprintf(" (%s)", kind))
+		}
+		if item.Deprecated {
+			output.WriteString(" [deprecated]")
+		}
+		if item.Detail != "" {
+			output.WriteString(" " + item.Detail)
+		}
+		output.WriteString("\n")
+
+		if item.Documentation != nil {
+			if doc := documentationText(item.Documentation.Value); doc != "" {
+				// Only the first paragraph, full documentation is available through hover
+				if idx := strings.Index(doc, "\n\n"); idx >= 0 {
+					doc = doc[:idx]
+				}
+				output.WriteString(fmt.Sprintf("   %s\n", strings.ReplaceAll(doc, "\n", "\n   ")))
+			}
+		}
+	}
+
+	return output.String(), nil
+}
+
+func completionSortKey(item protocol.CompletionItem) string {
+	if item.SortText != "" {
+		return item.SortText
+	}
+	return item.Label
+}
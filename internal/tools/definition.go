@@ -10,55 +10,22 @@ import (
 )
 
 func ReadDefinition(ctx context.Context, client *lsp.Client, symbolName string) (string, error) {
-	symbolResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
-		Query: symbolName,
-	})
+	symbols, err := FindSymbols(ctx, client, symbolName)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch symbol: %v", err)
-	}
-
-	results, err := symbolResult.Results()
-	if err != nil {
-		return "", fmt.Errorf("failed to parse results: %v", err)
+		return "", err
 	}
 
 	var definitions []string
-	for _, symbol := range results {
+	for _, symbol := range symbols {
 		kind := ""
 		container := ""
 
-		// Skip symbols that we are not looking for. workspace/symbol may return
-		// a large number of fuzzy matches.
-		switch v := symbol.(type) {
-		case *protocol.SymbolInformation:
-			// SymbolInformation results have richer data.
+		// SymbolInformation results have richer data.
+		if v, ok := symbol.(*protocol.SymbolInformation); ok {
 			kind = fmt.Sprintf("Kind: %s\n", protocol.TableKindMap[v.Kind])
 			if v.ContainerName != "" {
 				container = fmt.Sprintf("Container Name: %s\n", v.ContainerName)
 			}
-
-			// Handle different matching strategies based on the search term
-			if strings.Contains(symbolName, ".") {
-				// For qualified names like "Type.Method", require exact match
-				if symbol.GetName() != symbolName {
-					continue
-				}
-			} else {
-				// For unqualified names like "Method"
-				if v.Kind == protocol.Method {
-					// For methods, only match if the method name matches exactly Type.symbolName or Type::symbolName or symbolName
-					if !strings.HasSuffix(symbol.GetName(), "::"+symbolName) && !strings.HasSuffix(symbol.GetName(), "."+symbolName) && symbol.GetName() != symbolName {
-						continue
-					}
-				} else if symbol.GetName() != symbolName {
-					// For non-methods, exact match only
-					continue
-				}
-			}
-		default:
-			if symbol.GetName() != symbolName {
-				continue
-			}
 		}
 
 		toolsLogger.Debug("Found symbol: %s", symbol.GetName())
@@ -75,6 +42,7 @@ func ReadDefinition(ctx context.Context, client *lsp.Client, symbolName string)
 		locationInfo := fmt.Sprintf(
 			"Symbol: %s\n"+
 				"File: %s\n"+
+				formatFileHash(loc.URI.Path())+
 				kind+
 				container+
 				"Range: L%d:C%d - L%d:C%d\n\n",
@@ -102,3 +70,102 @@ func ReadDefinition(ctx context.Context, client *lsp.Client, symbolName string)
 
 	return strings.Join(definitions, ""), nil
 }
+
+// ReadDefinitionAtPosition returns the full source of the definition of the symbol at the
+// specified position. If the language server does not report a definition, the declaration
+// is used instead.
+func ReadDefinitionAtPosition(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
+
+	position := textDocumentPosition(filePath, line, column)
+
+	definitionResult, err := client.Definition(ctx, protocol.DefinitionParams{
+		TextDocumentPositionParams: position,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get definition: %v", err)
+	}
+
+	locations, err := definitionResult.Locations()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse results: %v", err)
+	}
+
+	if len(locations) == 0 {
+		declarationResult, err := client.Declaration(ctx, protocol.DeclarationParams{
+			TextDocumentPositionParams: position,
+		})
+		if err != nil {
+			// Not all servers support textDocument/declaration
+			toolsLogger.Debug("Failed to get declaration: %v", err)
+		} else if locations, err = declarationResult.Locations(); err != nil {
+			return "", fmt.Errorf("failed to parse results: %v", err)
+		}
+	}
+
+	definitions := formatLocationDefinitions(ctx, client, locations)
+	if len(definitions) == 0 {
+		return fmt.Sprintf("No definition found at %s L%d:C%d", filePath, line, column), nil
+	}
+
+	return strings.Join(definitions, ""), nil
+}
+
+// formatLocationDefinitions reads the full code block at each location and formats it
+// the same way ReadDefinition does.
+func formatLocationDefinitions(ctx context.Context, client *lsp.Client, locations []protocol.Location) []string {
+	var definitions []string
+	for _, loc := range locations {
+		err := client.OpenFile(ctx, loc.URI.Path())
+		if err != nil {
+			toolsLogger.Error("Error opening file: %v", err)
+			continue
+		}
+
+		// The location usually covers just the name of the symbol
+		symbol := ""
+		if loc.Range.Start.Line == loc.Range.End.Line {
+			if name, err := ExtractTextFromLocation(loc); err == nil && name != "" {
+				symbol = fmt.Sprintf("Symbol: %s\n", name)
+			}
+		}
+
+		definition, fullLoc, err := GetFullDefinition(ctx, client, loc)
+		if err != nil {
+			// Fall back to the lines of the location itself
+			toolsLogger.Debug("Could not find enclosing symbol at %s: %v", loc.URI, err)
+			fullLoc = loc
+			fullLoc.Range.Start.Character = 0
+			definition, err = ExtractTextFromLocation(protocol.Location{
+				URI: loc.URI,
+				Range: protocol.Range{
+					Start: protocol.Position{Line: loc.Range.Start.Line},
+					End:   protocol.Position{Line: loc.Range.End.Line + 1},
+				},
+			})
+			if err != nil {
+				toolsLogger.Error("Error getting definition: %v", err)
+				continue
+			}
+			definition = strings.TrimSuffix(definition, "\n")
+		}
+
+		locationInfo := fmt.Sprintf(
+			"%s"+
+				"File: %s\n"+
+				formatFileHash(fullLoc.URI.Path())+
+				"Range: %s\n\n",
+			symbol,
+			strings.TrimPrefix(string(fullLoc.URI), "file://"),
+			formatRange(fullLoc.Range),
+		)
+
+		definition = addLineNumbers(definition, int(fullLoc.Range.Start.Line)+1)
+
+		definitions = append(definitions, "---\n\n"+locationInfo+definition+"\n")
+	}
+	return definitions
+}
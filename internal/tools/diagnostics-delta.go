@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// captureDiagnostics opens the files an edit is about to touch and returns their current
+// diagnostics, to be compared with the diagnostics after the edit by diagnosticsDelta.
+// The diagnostics are waited for if they are not current, so that existing problems are
+// not reported as introduced by the edit.
+func captureDiagnostics(ctx context.Context, client *lsp.Client, paths []string) map[protocol.DocumentUri][]protocol.Diagnostic {
+	var opened []protocol.DocumentUri
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := client.OpenFile(ctx, path); err != nil {
+			toolsLogger.Error("Error opening file: %v", err)
+			continue
+		}
+		opened = append(opened, protocol.DocumentUri("file://"+path))
+	}
+	refreshDiagnostics(ctx, client, opened...)
+
+	before := make(map[protocol.DocumentUri][]protocol.Diagnostic, len(paths))
+	for _, path := range paths {
+		uri := protocol.DocumentUri("file://" + path)
+		before[uri] = client.GetFileDiagnostics(uri)
+	}
+	return before
+}
+
+// diagnosticsDelta notifies the server of the new content of the files in before, waits
+// for their diagnostics to be current again and describes the diagnostics that were
+// introduced or resolved. Diagnostics are compared without their ranges, which move
+// with the edit.
+func diagnosticsDelta(ctx context.Context, client *lsp.Client, before map[protocol.DocumentUri][]protocol.Diagnostic) string {
+	uris := make([]protocol.DocumentUri, 0, len(before))
+	for uri := range before {
+		uris = append(uris, uri)
+	}
+	sort.Slice(uris, func(i, j int) bool { return uris[i] < uris[j] })
+
+	var notified []protocol.DocumentUri
+	for _, uri := range uris {
+		path := uri.Path()
+		if _, err := os.Stat(path); err != nil {
+			// Deleted or renamed by the edit
+			continue
+		}
+
+		var err error
+		if client.IsFileOpen(path) {
+			err = client.NotifyChange(ctx, path)
+		} else {
+			err = client.OpenFile(ctx, path)
+		}
+		if err != nil {
+			toolsLogger.Error("Failed to notify the server of changes to %s: %v", path, err)
+			continue
+		}
+		notified = append(notified, uri)
+	}
+
+	pending := refreshDiagnostics(ctx, client, notified...)
+
+	var output strings.Builder
+	changed := false
+	for _, uri := range notified {
+		path := uri.Path()
+		introduced := subtractDiagnostics(client.GetFileDiagnostics(uri), before[uri])
+		resolved := subtractDiagnostics(before[uri], client.GetFileDiagnostics(uri))
+		if len(introduced) == 0 && len(resolved) == 0 {
+			continue
+		}
+		changed = true
+
+		output.WriteString(fmt.Sprintf("%s\nIntroduced: %d, resolved: %d\n", path, len(introduced), len(resolved)))
+		for _, diag := range introduced {
+			output.WriteString("+ " + formatDiagnostic(diag) + "\n")
+		}
+		for _, diag := range resolved {
+			output.WriteString("- " + formatDiagnostic(diag) + "\n")
+		}
+	}
+
+	for _, uri := range pending {
+		output.WriteString(fmt.Sprintf("%s\nThe language server did not report new diagnostics within %v, they may be out of date\n", uri.Path(), client.DiagnosticsTimeout()))
+	}
+
+	if !changed {
+		return "\nNo diagnostics were introduced or resolved.\n" + output.String()
+	}
+	return "\nDiagnostics introduced (+) or resolved (-):\n" + output.String()
+}
+
+// subtractDiagnostics returns the diagnostics in a that have no counterpart with the same
+// severity, source, code and message in b
+func subtractDiagnostics(a, b []protocol.Diagnostic) []protocol.Diagnostic {
+	counts := make(map[string]int)
+	for _, diag := range b {
+		counts[diagnosticKey(diag)]++
+	}
+
+	var result []protocol.Diagnostic
+	for _, diag := range a {
+		key := diagnosticKey(diag)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		result = append(result, diag)
+	}
+	return result
+}
+
+func diagnosticKey(diag protocol.Diagnostic) string {
+	return fmt.Sprintf("%d\x00%s\x00%v\x00%s", diag.Severity, diag.Source, diag.Code, diag.Message)
+}
@@ -6,14 +6,14 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/isaacphi/mcp-language-server/internal/lsp"
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
-// GetDiagnosticsForFile retrieves diagnostics for a specific file from the language server
-func GetDiagnosticsForFile(ctx context.Context, client *lsp.Client, filePath string, contextLines int, showLineNumbers bool) (string, error) {
+// GetDiagnosticsForFile retrieves diagnostics for a specific file from the language server.
+// If showFixes is set, the titles of the quick fixes offered for each diagnostic are listed.
+func GetDiagnosticsForFile(ctx context.Context, client *lsp.Client, filePath string, contextLines int, showLineNumbers bool, showFixes bool) (string, error) {
 	// Override with environment variable if specified
 	if envLines := os.Getenv("LSP_CONTEXT_LINES"); envLines != "" {
 		if val, err := strconv.Atoi(envLines); err == nil && val >= 0 {
@@ -26,21 +26,10 @@ func GetDiagnosticsForFile(ctx context.Context, client *lsp.Client, filePath str
 		return "", fmt.Errorf("could not open file: %v", err)
 	}
 
-	// Wait for diagnostics
-	// TODO: wait for notification
-	time.Sleep(time.Second * 3)
-
 	// Convert the file path to URI format
 	uri := protocol.DocumentUri("file://" + filePath)
 
-	// Request fresh diagnostics
-	diagParams := protocol.DocumentDiagnosticParams{
-		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
-	}
-	_, err = client.Diagnostic(ctx, diagParams)
-	if err != nil {
-		toolsLogger.Error("Failed to get diagnostics: %v", err)
-	}
+	refreshDiagnostics(ctx, client, uri)
 
 	// Get diagnostics from the cache
 	diagnostics := client.GetFileDiagnostics(uri)
@@ -60,27 +49,10 @@ func GetDiagnosticsForFile(ctx context.Context, client *lsp.Client, filePath str
 	var diagLocations []protocol.Location
 
 	for _, diag := range diagnostics {
-		severity := getSeverityString(diag.Severity)
-		location := fmt.Sprintf("L%d:C%d",
-			diag.Range.Start.Line+1,
-			diag.Range.Start.Character+1)
-
-		summary := fmt.Sprintf("%s at %s: %s",
-			severity,
-			location,
-			diag.Message)
-
-		// Add source and code if available
-		if diag.Source != "" {
-			summary += fmt.Sprintf(" (Source: %s", diag.Source)
-			if diag.Code != nil {
-				summary += fmt.Sprintf(", Code: %v", diag.Code)
-			}
-			summary += ")"
-		} else if diag.Code != nil {
-			summary += fmt.Sprintf(" (Code: %v)", diag.Code)
+		summary := formatDiagnostic(diag)
+		if showFixes {
+			summary += formatQuickFixes(ctx, client, uri, diag)
 		}
-
 		diagSummaries = append(diagSummaries, summary)
 
 		// Create a location for this diagnostic to use with line ranges
@@ -135,6 +107,121 @@ func GetDiagnosticsForFile(ctx context.Context, client *lsp.Client, filePath str
 	return result, nil
 }
 
+// refreshDiagnostics makes the cached diagnostics of files current. Servers that support
+// pull diagnostics return them directly, others publish them once they have checked the
+// current version of each file. It returns the files whose diagnostics may be out of date.
+func refreshDiagnostics(ctx context.Context, client *lsp.Client, uris ...protocol.DocumentUri) []protocol.DocumentUri {
+	if !client.SupportsPullDiagnostics() {
+		return client.WaitForDiagnostics(ctx, uris...)
+	}
+	for _, uri := range uris {
+		if err := client.PullDiagnostics(ctx, uri); err != nil {
+			toolsLogger.Debug("Failed to pull diagnostics for %s, waiting for them to be published: %v", uri, err)
+		}
+	}
+	return client.WaitForDiagnostics(ctx, uris...)
+}
+
+// formatDiagnostic summarizes a diagnostic on one line with its severity, location,
+// message, source, code and tags, followed by indented lines with the documentation link
+// and the related locations with their source line
+func formatDiagnostic(diag protocol.Diagnostic) string {
+	severity := getSeverityString(diag.Severity)
+	location := fmt.Sprintf("L%d:C%d",
+		diag.Range.Start.Line+1,
+		diag.Range.Start.Character+1)
+
+	summary := fmt.Sprintf("%s at %s: %s",
+		severity,
+		location,
+		diag.Message)
+
+	// Add source and code if available
+	if diag.Source != "" {
+		summary += fmt.Sprintf(" (Source: %s", diag.Source)
+		if diag.Code != nil {
+			summary += fmt.Sprintf(", Code: %v", diag.Code)
+		}
+		summary += ")"
+	} else if diag.Code != nil {
+		summary += fmt.Sprintf(" (Code: %v)", diag.Code)
+	}
+
+	for _, tag := range diag.Tags {
+		switch tag {
+		case protocol.Unnecessary:
+			summary += " [unnecessary]"
+		case protocol.Deprecated:
+			summary += " [deprecated]"
+		}
+	}
+
+	if diag.CodeDescription != nil && diag.CodeDescription.Href != "" {
+		summary += fmt.Sprintf("\n    Documentation: %s", diag.CodeDescription.Href)
+	}
+
+	// Related locations, such as the declaration a compiler error refers to
+	for _, related := range diag.RelatedInformation {
+		path := related.Location.URI.Path()
+		line := related.Location.Range.Start.Line
+		summary += fmt.Sprintf("\n    Related: %s:L%d:C%d", path, line+1, related.Location.Range.Start.Character+1)
+		if related.Message != "" {
+			summary += ": " + related.Message
+		}
+		if text, ok := readLine(path, int(line)); ok {
+			summary += fmt.Sprintf("\n      %d|%s", line+1, text)
+		}
+	}
+
+	return summary
+}
+
+// formatQuickFixes lists the titles of the quick fixes the server offers for a diagnostic,
+// one indented line each
+func formatQuickFixes(ctx context.Context, client *lsp.Client, uri protocol.DocumentUri, diag protocol.Diagnostic) string {
+	params := protocol.CodeActionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Range:        diag.Range,
+		Context: protocol.CodeActionContext{
+			Diagnostics: []protocol.Diagnostic{diag},
+			Only:        []protocol.CodeActionKind{protocol.QuickFix},
+		},
+	}
+
+	result, err := client.CodeAction(ctx, params)
+	if err != nil {
+		toolsLogger.Debug("Failed to get quick fixes for %s: %v", uri, err)
+		return ""
+	}
+
+	var output strings.Builder
+	for _, item := range result {
+		switch v := item.Value.(type) {
+		case protocol.CodeAction:
+			output.WriteString("\n    Fix: " + v.Title)
+			if v.IsPreferred {
+				output.WriteString(" [preferred]")
+			}
+		case protocol.Command:
+			output.WriteString("\n    Fix: " + v.Title)
+		}
+	}
+	return output.String()
+}
+
+// readLine returns a line of a file without its line ending
+func readLine(path string, line int) (string, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	lines := strings.Split(string(content), "\n")
+	if line < 0 || line >= len(lines) {
+		return "", false
+	}
+	return strings.TrimRight(lines[line], "\r"), true
+}
+
 func getSeverityString(severity protocol.DiagnosticSeverity) string {
 	switch severity {
 	case protocol.SeverityError:
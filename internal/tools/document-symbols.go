@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetDocumentSymbols returns an outline of the symbols in a file. If kinds is not empty, only
+// symbols of those kinds are listed. If maxDepth is greater than zero, nested symbols deeper
+// than maxDepth levels are omitted.
+func GetDocumentSymbols(ctx context.Context, client *lsp.Client, filePath string, kinds []string, maxDepth int) (string, error) {
+	kindFilter, err := parseSymbolKinds(kinds)
+	if err != nil {
+		return "", err
+	}
+
+	err = client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
+
+	symResult, err := client.DocumentSymbol(ctx, protocol.DocumentSymbolParams{
+		TextDocument: protocol.TextDocumentIdentifier{
+			URI: protocol.DocumentUri("file://" + filePath),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get document symbols: %v", err)
+	}
+
+	symbols, err := symResult.Results()
+	if err != nil {
+		return "", fmt.Errorf("failed to process document symbols: %v", err)
+	}
+
+	var outline strings.Builder
+	count := 0
+
+	// level is the nesting level in the symbol tree, indent is the nesting level in the output,
+	// which only differ when parents are filtered out by kind
+	var writeSymbols func(symbols []protocol.DocumentSymbolResult, level, indent int, container string)
+	writeSymbols = func(symbols []protocol.DocumentSymbolResult, level, indent int, container string) {
+		for _, sym := range symbols {
+			var kind protocol.SymbolKind
+			var detail string
+			var children []protocol.DocumentSymbol
+			symContainer := container
+			switch v := sym.(type) {
+			case *protocol.DocumentSymbol:
+				kind = v.Kind
+				detail = v.Detail
+				children = v.Children
+			case *protocol.SymbolInformation:
+				kind = v.Kind
+				symContainer = v.ContainerName
+			}
+
+			childIndent := indent
+			if len(kindFilter) == 0 || kindFilter[kind] {
+				rng := sym.GetRange()
+				outline.WriteString(strings.Repeat("  ", indent))
+				outline.WriteString(fmt.Sprintf("%s %s", protocol.TableKindMap[kind], sym.GetName()))
+				if detail != "" {
+					outline.WriteString(" " + detail)
+				}
+				// Parents may not be shown when filtering, so name them explicitly
+				if symContainer != "" && len(kindFilter) > 0 {
+					outline.WriteString(fmt.Sprintf(" (in %s)", symContainer))
+				}
+				if rng.Start.Line == rng.End.Line {
+					outline.WriteString(fmt.Sprintf(" L%d\n", rng.Start.Line+1))
+				} else {
+					outline.WriteString(fmt.Sprintf(" L%d-L%d\n", rng.Start.Line+1, rng.End.Line+1))
+				}
+				count++
+				childIndent++
+			}
+
+			if len(children) == 0 || (maxDepth > 0 && level+1 >= maxDepth) {
+				continue
+			}
+
+			childContainer := sym.GetName()
+			if container != "" {
+				childContainer = container + "." + childContainer
+			}
+
+			childSymbols := make([]protocol.DocumentSymbolResult, len(children))
+			for i := range children {
+				childSymbols[i] = &children[i]
+			}
+			writeSymbols(childSymbols, level+1, childIndent, childContainer)
+		}
+	}
+	writeSymbols(symbols, 0, 0, "")
+
+	if count == 0 {
+		return "No symbols found in " + filePath, nil
+	}
+
+	return fmt.Sprintf("%s\nSymbols: %d\n\n%s", filePath, count, outline.String()), nil
+}
+
+// parseSymbolKinds converts symbol kind names like "Function" or "method" to a set of SymbolKinds
+func parseSymbolKinds(kinds []string) (map[protocol.SymbolKind]bool, error) {
+	kindFilter := make(map[protocol.SymbolKind]bool)
+	for _, name := range kinds {
+		found := false
+		for kind, kindName := range protocol.TableKindMap {
+			if strings.EqualFold(kindName, name) {
+				kindFilter[kind] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			validKinds := make([]string, 0, len(protocol.TableKindMap))
+			for _, kindName := range protocol.TableKindMap {
+				validKinds = append(validKinds, kindName)
+			}
+			sort.Strings(validKinds)
+			return nil, fmt.Errorf("unknown symbol kind: %s. Valid kinds are: %s", name, strings.Join(validKinds, ", "))
+		}
+	}
+	return kindFilter, nil
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"sort"
@@ -14,17 +16,54 @@ import (
 )
 
 type TextEdit struct {
-	StartLine int    `json:"startLine" jsonschema:"required,description=Start line to replace, inclusive"`
-	EndLine   int    `json:"endLine" jsonschema:"required,description=End line to replace, inclusive"`
+	StartLine int    `json:"startLine" jsonschema:"description=Start line to replace, inclusive"`
+	EndLine   int    `json:"endLine" jsonschema:"description=End line to replace, inclusive"`
 	NewText   string `json:"newText" jsonschema:"description=Replacement text. Replace with the new text. Leave blank to remove lines."`
+	// OldText anchors the edit on text instead of line numbers. If set, StartLine and
+	// EndLine are ignored and the only occurrence of OldText in the file is replaced.
+	OldText *string `json:"oldText,omitempty" jsonschema:"description=Text to replace, which must occur exactly once in the file. Used instead of startLine and endLine."`
+	// ExpectedText and ExpectedFileHash guard against editing a file that changed since it
+	// was read. If set, the edit is rejected unless the lines still hold ExpectedText or the
+	// SHA-256 of the whole file is still ExpectedFileHash.
+	ExpectedText     *string `json:"expectedText,omitempty" jsonschema:"description=The current text of the lines being replaced. The edit is rejected if the file does not match."`
+	ExpectedFileHash string  `json:"expectedFileHash,omitempty" jsonschema:"description=The SHA-256 of the file content as returned by the definition tool or a previous edit. The edit is rejected if the file does not match."`
+
+	// rng is the range matched by OldText
+	rng *protocol.Range
 }
 
-func ApplyTextEdits(ctx context.Context, client *lsp.Client, filePath string, edits []TextEdit) (string, error) {
+// ApplyTextEdits applies edits to a file. If showDiagnostics is set, the result also lists
+// the diagnostics introduced or resolved by the edits once the server has published them.
+func ApplyTextEdits(ctx context.Context, client *lsp.Client, filePath string, edits []TextEdit, showDiagnostics bool) (string, error) {
 	err := client.OpenFile(ctx, filePath)
 	if err != nil {
 		return "", fmt.Errorf("could not open file: %v", err)
 	}
 
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	// Resolve edits anchored on text to the lines of their match
+	for i := range edits {
+		if edits[i].OldText == nil {
+			continue
+		}
+		rng, err := findUniqueText(content, *edits[i].OldText)
+		if err != nil {
+			return "", fmt.Errorf("edit %d: %v", i+1, err)
+		}
+		edits[i].rng = &rng
+		edits[i].StartLine = int(rng.Start.Line) + 1
+		edits[i].EndLine = int(rng.End.Line) + 1
+		if rng.End.Character == 0 && rng.End.Line > rng.Start.Line {
+			// The match ends with a newline, so it doesn't touch the following line
+			edits[i].EndLine--
+		}
+		edits[i].NewText = strings.ReplaceAll(edits[i].NewText, "\r\n", "\n")
+	}
+
 	// Create a sorted copy of edits for reporting
 	sortedEdits := make([]TextEdit, len(edits))
 	copy(sortedEdits, edits)
@@ -36,6 +75,14 @@ func ApplyTextEdits(ctx context.Context, client *lsp.Client, filePath string, ed
 	linesRemovedSorted := 0
 	linesAddedSorted := 0
 	for _, edit := range sortedEdits {
+		if edit.rng != nil {
+			// Edits anchored on text may change only part of a line, so compare the text itself
+			removed, added := countChangedLines(strings.ReplaceAll(*edit.OldText, "\r\n", "\n"), edit.NewText)
+			linesRemovedSorted += removed
+			linesAddedSorted += added
+			continue
+		}
+
 		// Calculate lines removed: end - start + 1
 		removedLineCount := edit.EndLine - edit.StartLine + 1
 		linesRemovedSorted += removedLineCount
@@ -50,6 +97,11 @@ func ApplyTextEdits(ctx context.Context, client *lsp.Client, filePath string, ed
 		linesAddedSorted += addedLineCount
 	}
 
+	// Reject the edits if the file changed since the caller read it
+	if err := checkExpectedContent(content, sortedEdits); err != nil {
+		return "", err
+	}
+
 	// Sort edits by line number in descending order to process from bottom to top
 	// This way line numbers don't shift under us as we make edits
 	sort.Slice(edits, func(i, j int) bool {
@@ -59,6 +111,14 @@ func ApplyTextEdits(ctx context.Context, client *lsp.Client, filePath string, ed
 	// Convert from input format to protocol.TextEdit
 	var textEdits []protocol.TextEdit
 	for _, edit := range edits {
+		if edit.rng != nil {
+			textEdits = append(textEdits, protocol.TextEdit{
+				Range:   *edit.rng,
+				NewText: edit.NewText,
+			})
+			continue
+		}
+
 		// Get the range covering the requested lines
 		rng, err := getRange(edit.StartLine, edit.EndLine, filePath)
 		if err != nil {
@@ -78,11 +138,153 @@ func ApplyTextEdits(ctx context.Context, client *lsp.Client, filePath string, ed
 		},
 	}
 
-	if err := utilities.ApplyWorkspaceEdit(edit); err != nil {
+	var diagnosticsBefore map[protocol.DocumentUri][]protocol.Diagnostic
+	if showDiagnostics {
+		diagnosticsBefore = captureDiagnostics(ctx, client, []string{filePath})
+	}
+
+	if err := utilities.ApplyWorkspaceEdit(edit, fmt.Sprintf("Edit %s", filePath)); err != nil {
 		return "", fmt.Errorf("failed to apply text edits: %v", err)
 	}
 
-	return fmt.Sprintf("Successfully applied text edits. %d lines removed, %d lines added.", linesRemovedSorted, linesAddedSorted), nil
+	result := fmt.Sprintf("Successfully applied text edits. %s removed, %s added.", pluralLines(linesRemovedSorted), pluralLines(linesAddedSorted))
+
+	// Return the new hash so that further edits can be checked against it
+	newContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %v", err)
+	}
+	result += fmt.Sprintf("\nFile hash: %s", fileHash(newContent))
+
+	if showDiagnostics {
+		result += "\n" + diagnosticsDelta(ctx, client, diagnosticsBefore)
+	}
+
+	return result, nil
+}
+
+// checkExpectedContent checks the expected text and file hash of each edit against the
+// current content of the file, and reports every mismatch with the current content of
+// the lines
+func checkExpectedContent(content []byte, edits []TextEdit) error {
+	hash := fileHash(content)
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+
+	var mismatches strings.Builder
+	count := 0
+	for _, edit := range edits {
+		var reason string
+		current := currentLines(lines, edit.StartLine, edit.EndLine)
+		if edit.ExpectedFileHash != "" && !strings.EqualFold(edit.ExpectedFileHash, hash) {
+			reason = fmt.Sprintf("file hash is %s, expected %s", hash, edit.ExpectedFileHash)
+		} else if edit.ExpectedText != nil && edit.OldText == nil && normalizeExpectedText(*edit.ExpectedText) != current {
+			reason = "text does not match the expected text"
+		}
+		if reason == "" {
+			continue
+		}
+
+		count++
+		mismatches.WriteString(fmt.Sprintf("\nL%d-L%d: %s\n", edit.StartLine, edit.EndLine, reason))
+		if current == "" {
+			mismatches.WriteString("Current content: (no lines)\n")
+		} else {
+			mismatches.WriteString("Current content:\n")
+			mismatches.WriteString(addLineNumbers(current, edit.StartLine))
+		}
+	}
+
+	if count == 0 {
+		return nil
+	}
+	return fmt.Errorf("the file has changed since it was read, %d of %d edits do not match and no changes were made. Read the file again and retry.\n%s", count, len(edits), mismatches.String())
+}
+
+// findUniqueText returns the range of the only occurrence of text in content. Line endings
+// in text match either line ending style, so text can be given with \n for files that use
+// \r\n.
+func findUniqueText(content []byte, text string) (protocol.Range, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return protocol.Range{}, fmt.Errorf("oldText must not be empty")
+	}
+
+	// Positions are counted in lines split on the file's line ending, as in getRange
+	normalized := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	var matches []int
+	for offset := 0; offset < len(normalized); {
+		idx := strings.Index(normalized[offset:], text)
+		if idx < 0 {
+			break
+		}
+		matches = append(matches, offset+idx)
+		offset += idx + 1
+	}
+
+	switch len(matches) {
+	case 0:
+		return protocol.Range{}, fmt.Errorf("oldText was not found in the file. Check that it matches the current content exactly, including whitespace and indentation")
+	case 1:
+	default:
+		var locations []string
+		for _, match := range matches {
+			pos := offsetToPosition(normalized, match)
+			locations = append(locations, fmt.Sprintf("L%d:C%d", pos.Line+1, pos.Character+1))
+		}
+		return protocol.Range{}, fmt.Errorf("oldText is ambiguous, it was found %d times at %s. Include more surrounding text to make it unique", len(matches), strings.Join(locations, ", "))
+	}
+
+	return protocol.Range{
+		Start: offsetToPosition(normalized, matches[0]),
+		End:   offsetToPosition(normalized, matches[0]+len(text)),
+	}, nil
+}
+
+// offsetToPosition converts a byte offset in text to a line and byte column
+func offsetToPosition(text string, offset int) protocol.Position {
+	before := text[:offset]
+	line := strings.Count(before, "\n")
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return protocol.Position{
+		Line:      uint32(line),
+		Character: uint32(offset - lineStart),
+	}
+}
+
+// currentLines returns lines startLine to endLine (1-indexed, inclusive), or an empty
+// string if the range is past the end of the file
+func currentLines(lines []string, startLine, endLine int) string {
+	if startLine < 1 || startLine > len(lines) || endLine < startLine {
+		return ""
+	}
+	if endLine > len(lines) {
+		endLine = len(lines)
+	}
+	return strings.Join(lines[startLine-1:endLine], "\n")
+}
+
+// normalizeExpectedText ignores line ending style and a trailing newline in expected text
+func normalizeExpectedText(text string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// fileHash returns the hex encoded SHA-256 of file content
+func fileHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// formatFileHash returns a line with the hash of a file, for output that edits may be
+// based on, or an empty string if the file can't be read
+func formatFileHash(filePath string) string {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		toolsLogger.Debug("Failed to read %s for its hash: %v", filePath, err)
+		return ""
+	}
+	return fmt.Sprintf("File hash: %s\n", fileHash(content))
 }
 
 // getRange creates a protocol.Range that covers the specified start and end lines
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// FormatFile formats a file with the language server's formatter and writes the result.
+// If startLine is greater than zero, only lines startLine to endLine (1-indexed, inclusive)
+// are formatted, which requires the server to support range formatting.
+func FormatFile(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine, tabSize int, insertSpaces bool) (string, error) {
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
+
+	before, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	uri := protocol.DocumentUri("file://" + filePath)
+	options := protocol.FormattingOptions{
+		TabSize:      uint32(tabSize),
+		InsertSpaces: insertSpaces,
+	}
+
+	var edits []protocol.TextEdit
+	if startLine > 0 {
+		if endLine < startLine {
+			return "", fmt.Errorf("endLine must not be before startLine")
+		}
+		edits, err = client.RangeFormatting(ctx, protocol.DocumentRangeFormattingParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Range: protocol.Range{
+				Start: protocol.Position{Line: uint32(startLine - 1)},
+				// End at the start of the line after the range so the whole last line is included
+				End: protocol.Position{Line: uint32(endLine)},
+			},
+			Options: options,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to format range: %v", err)
+		}
+	} else {
+		edits, err = client.Formatting(ctx, protocol.DocumentFormattingParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Options:      options,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to format file: %v", err)
+		}
+	}
+
+	if len(edits) == 0 {
+		return fmt.Sprintf("%s\nAlready formatted, no changes made", filePath), nil
+	}
+
+	edit := protocol.WorkspaceEdit{
+		Changes: map[protocol.DocumentUri][]protocol.TextEdit{uri: edits},
+	}
+	if err := utilities.ApplyWorkspaceEdit(edit, fmt.Sprintf("Format %s", filePath)); err != nil {
+		return "", fmt.Errorf("failed to apply formatting: %v", err)
+	}
+
+	after, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read formatted file: %v", err)
+	}
+
+	removed, added := countChangedLines(string(before), string(after))
+	if removed == 0 && added == 0 {
+		return fmt.Sprintf("%s\nAlready formatted, no changes made", filePath), nil
+	}
+
+	return fmt.Sprintf("%s\nFormatted with %d edits. %s removed, %s added.", filePath, len(edits), pluralLines(removed), pluralLines(added)), nil
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetHighlights lists every occurrence in a file of the symbol at a position, grouped into
+// writes, reads and other textual occurrences, each with contextLines lines of context
+func GetHighlights(ctx context.Context, client *lsp.Client, filePath string, line, column, contextLines int) (string, error) {
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
+
+	highlights, err := client.DocumentHighlight(ctx, protocol.DocumentHighlightParams{
+		TextDocumentPositionParams: textDocumentPosition(filePath, line, column),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get highlights: %v", err)
+	}
+
+	if len(highlights) == 0 {
+		return fmt.Sprintf("%s\nNo highlights found at L%d:C%d", filePath, line, column), nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+
+	sort.Slice(highlights, func(i, j int) bool {
+		if highlights[i].Range.Start.Line != highlights[j].Range.Start.Line {
+			return highlights[i].Range.Start.Line < highlights[j].Range.Start.Line
+		}
+		return highlights[i].Range.Start.Character < highlights[j].Range.Start.Character
+	})
+
+	// Servers that don't distinguish reads from writes return text highlights
+	groups := map[protocol.DocumentHighlightKind][]protocol.DocumentHighlight{}
+	for _, highlight := range highlights {
+		kind := highlight.Kind
+		if kind == 0 {
+			kind = protocol.Text
+		}
+		groups[kind] = append(groups[kind], highlight)
+	}
+
+	symbol, err := ExtractTextFromLocation(protocol.Location{
+		URI:   protocol.DocumentUri("file://" + filePath),
+		Range: highlights[0].Range,
+	})
+	if err != nil {
+		toolsLogger.Warn("failed to extract highlighted text: %v", err)
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s\nOccurrences of %s: %d\n", filePath, symbol, len(highlights)))
+
+	for _, group := range []struct {
+		kind protocol.DocumentHighlightKind
+		name string
+	}{
+		{protocol.Write, "Writes"},
+		{protocol.Read, "Reads"},
+		{protocol.Text, "Other occurrences"},
+	} {
+		groupHighlights := groups[group.kind]
+		if len(groupHighlights) == 0 {
+			continue
+		}
+
+		var locStrings []string
+		linesToShow := make(map[int]bool)
+		for _, highlight := range groupHighlights {
+			locStrings = append(locStrings, fmt.Sprintf("L%d:C%d",
+				highlight.Range.Start.Line+1,
+				highlight.Range.Start.Character+1))
+
+			refLine := int(highlight.Range.Start.Line)
+			for i := refLine - contextLines; i <= refLine+contextLines; i++ {
+				linesToShow[i] = true
+			}
+		}
+
+		output.WriteString(fmt.Sprintf("\n%s: %d\n", group.name, len(groupHighlights)))
+		output.WriteString("At: " + strings.Join(locStrings, ", ") + "\n\n")
+		output.WriteString(FormatLinesWithRanges(lines, ConvertLinesToRanges(linesToShow, len(lines))))
+	}
+
+	return output.String(), nil
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// FindImplementations returns the full source of every implementation of the symbol at the
+// specified position, e.g. the concrete types behind an interface or the methods implementing
+// an interface method
+func FindImplementations(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
+
+	result, err := client.Implementation(ctx, protocol.ImplementationParams{
+		TextDocumentPositionParams: textDocumentPosition(filePath, line, column),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get implementations: %v", err)
+	}
+
+	locations, err := result.Locations()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse results: %v", err)
+	}
+
+	definitions := formatLocationDefinitions(ctx, client, locations)
+	if len(definitions) == 0 {
+		return fmt.Sprintf("No implementations found at %s L%d:C%d", filePath, line, column), nil
+	}
+
+	return strings.Join(definitions, ""), nil
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetInlayHints returns lines startLine to endLine (1-indexed, inclusive) of a file with the
+// language server's inlay hints, such as inferred types and parameter names, inlined as
+// comments. If startLine is zero the whole file is returned.
+func GetInlayHints(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int) (string, error) {
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+
+	if startLine <= 0 {
+		startLine = 1
+		endLine = len(lines)
+		// Don't count the empty string after a trailing newline as a line
+		if endLine > 1 && lines[endLine-1] == "" {
+			endLine--
+		}
+	}
+	if endLine < startLine {
+		return "", fmt.Errorf("endLine must not be before startLine")
+	}
+	if startLine > len(lines) {
+		return "", fmt.Errorf("startLine %d is out of range, the file has %d lines", startLine, len(lines))
+	}
+	if endLine > len(lines) {
+		endLine = len(lines)
+	}
+
+	hints, err := client.InlayHint(ctx, protocol.InlayHintParams{
+		TextDocument: protocol.TextDocumentIdentifier{
+			URI: protocol.DocumentUri("file://" + filePath),
+		},
+		Range: protocol.Range{
+			Start: protocol.Position{Line: uint32(startLine - 1)},
+			End:   protocol.Position{Line: uint32(endLine - 1), Character: utf16Len(lines[endLine-1])},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get inlay hints: %v", err)
+	}
+
+	// Insert hints from the end of each line so earlier positions stay valid
+	sort.SliceStable(hints, func(i, j int) bool {
+		if hints[i].Position.Line != hints[j].Position.Line {
+			return hints[i].Position.Line < hints[j].Position.Line
+		}
+		return hints[i].Position.Character > hints[j].Position.Character
+	})
+
+	count := 0
+	for _, hint := range hints {
+		lineIdx := int(hint.Position.Line)
+		if lineIdx < startLine-1 || lineIdx > endLine-1 {
+			continue
+		}
+		lineText := lines[lineIdx]
+		// Positions are in UTF-16 code units, which differ from bytes on non-ASCII lines
+		char, ok := utf16OffsetToByte(lineText, hint.Position.Character)
+		if !ok {
+			char = len(lineText)
+		}
+		lines[lineIdx] = lineText[:char] + formatInlayHint(hint) + lineText[char:]
+		count++
+	}
+
+	if count == 0 {
+		return fmt.Sprintf("%s\nNo inlay hints found in L%d-L%d", filePath, startLine, endLine), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s\nInlay hints in L%d-L%d: %d\n\n", filePath, startLine, endLine, count))
+	output.WriteString(addLineNumbers(strings.Join(lines[startLine-1:endLine], "\n"), startLine))
+
+	return output.String(), nil
+}
+
+// formatInlayHint formats a hint as a comment, e.g. " /*: int */" for a type or
+// "/* name: */ " for a parameter name
+func formatInlayHint(hint protocol.InlayHint) string {
+	var label string
+	switch v := hint.Label.Value.(type) {
+	case string:
+		label = v
+	case []protocol.InlayHintLabelPart:
+		for _, part := range v {
+			label += part.Value
+		}
+	}
+	label = strings.TrimSpace(label)
+
+	switch hint.Kind {
+	case protocol.Type:
+		// Type hints follow the name they annotate
+		return fmt.Sprintf(" /*: %s */", strings.TrimSpace(strings.TrimPrefix(label, ":")))
+	case protocol.Parameter:
+		// Parameter hints precede the argument they name
+		if !strings.HasSuffix(label, ":") {
+			label += ":"
+		}
+		return fmt.Sprintf("/* %s */ ", label)
+	default:
+		return fmt.Sprintf(" /* %s */", label)
+	}
+}
@@ -11,6 +11,54 @@ import (
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
+// FindSymbols queries workspace/symbol and keeps only the results that match symbolName.
+// workspace/symbol may return a large number of fuzzy matches.
+func FindSymbols(ctx context.Context, client *lsp.Client, symbolName string) ([]protocol.WorkspaceSymbolResult, error) {
+	symbolResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
+		Query: symbolName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch symbol: %v", err)
+	}
+
+	results, err := symbolResult.Results()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse results: %v", err)
+	}
+
+	var symbols []protocol.WorkspaceSymbolResult
+	for _, symbol := range results {
+		if matchesSymbolName(symbol, symbolName) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols, nil
+}
+
+func matchesSymbolName(symbol protocol.WorkspaceSymbolResult, symbolName string) bool {
+	v, ok := symbol.(*protocol.SymbolInformation)
+	if !ok {
+		return symbol.GetName() == symbolName
+	}
+
+	// Handle different matching strategies based on the search term
+	if strings.Contains(symbolName, ".") {
+		// For qualified names like "Type.Method", require exact match
+		return symbol.GetName() == symbolName
+	}
+
+	// For unqualified names like "Method"
+	if v.Kind == protocol.Method {
+		// For methods, only match if the method name matches exactly Type.symbolName or Type::symbolName or symbolName
+		return strings.HasSuffix(symbol.GetName(), "::"+symbolName) ||
+			strings.HasSuffix(symbol.GetName(), "."+symbolName) ||
+			symbol.GetName() == symbolName
+	}
+
+	// For non-methods, exact match only
+	return symbol.GetName() == symbolName
+}
+
 // Gets the full code block surrounding the start of the input location
 func GetFullDefinition(ctx context.Context, client *lsp.Client, startLocation protocol.Location) (string, protocol.Location, error) {
 	symParams := protocol.DocumentSymbolParams{
@@ -82,46 +130,7 @@ func GetFullDefinition(ctx context.Context, client *lsp.Client, startLocation pr
 			return "", protocol.Location{}, fmt.Errorf("line number out of range")
 		}
 
-		line := lines[symbolRange.End.Line]
-		trimmedLine := strings.TrimSpace(line)
-
-		// In some cases (python), constant definitions do not include the full body and instead
-		// end with an opening bracket. In this case, parse the file until the closing bracket
-		if len(trimmedLine) > 0 {
-			lastChar := trimmedLine[len(trimmedLine)-1]
-			if lastChar == '(' || lastChar == '[' || lastChar == '{' || lastChar == '<' {
-				// Find matching closing bracket
-				bracketStack := []rune{rune(lastChar)}
-				lineNum := symbolRange.End.Line + 1
-
-				for lineNum < uint32(len(lines)) {
-					line := lines[lineNum]
-					for pos, char := range line {
-						if char == '(' || char == '[' || char == '{' || char == '<' {
-							bracketStack = append(bracketStack, char)
-						} else if char == ')' || char == ']' || char == '}' || char == '>' {
-							if len(bracketStack) > 0 {
-								lastOpen := bracketStack[len(bracketStack)-1]
-								if (lastOpen == '(' && char == ')') ||
-									(lastOpen == '[' && char == ']') ||
-									(lastOpen == '{' && char == '}') ||
-									(lastOpen == '<' && char == '>') {
-									bracketStack = bracketStack[:len(bracketStack)-1]
-									if len(bracketStack) == 0 {
-										// Found matching bracket - update range
-										symbolRange.End.Line = lineNum
-										symbolRange.End.Character = uint32(pos + 1)
-										goto foundClosing
-									}
-								}
-							}
-						}
-					}
-					lineNum++
-				}
-			foundClosing:
-			}
-		}
+		symbolRange = extendToClosingBracket(lines, symbolRange)
 
 		// Update location with new range
 		startLocation.Range = symbolRange
@@ -138,6 +147,48 @@ func GetFullDefinition(ctx context.Context, client *lsp.Client, startLocation pr
 	return "", protocol.Location{}, fmt.Errorf("symbol not found")
 }
 
+// extendToClosingBracket extends a range that ends with an opening bracket to the line of
+// its closing bracket. In some cases (python), constant definitions do not include the full
+// body and instead end with an opening bracket.
+func extendToClosingBracket(lines []string, symbolRange protocol.Range) protocol.Range {
+	trimmedLine := strings.TrimSpace(lines[symbolRange.End.Line])
+	if len(trimmedLine) == 0 {
+		return symbolRange
+	}
+	lastChar := trimmedLine[len(trimmedLine)-1]
+	if lastChar != '(' && lastChar != '[' && lastChar != '{' && lastChar != '<' {
+		return symbolRange
+	}
+
+	// Find matching closing bracket
+	bracketStack := []rune{rune(lastChar)}
+	for lineNum := symbolRange.End.Line + 1; lineNum < uint32(len(lines)); lineNum++ {
+		for pos, char := range lines[lineNum] {
+			if char == '(' || char == '[' || char == '{' || char == '<' {
+				bracketStack = append(bracketStack, char)
+			} else if char == ')' || char == ']' || char == '}' || char == '>' {
+				if len(bracketStack) == 0 {
+					continue
+				}
+				lastOpen := bracketStack[len(bracketStack)-1]
+				if (lastOpen == '(' && char == ')') ||
+					(lastOpen == '[' && char == ']') ||
+					(lastOpen == '{' && char == '}') ||
+					(lastOpen == '<' && char == '>') {
+					bracketStack = bracketStack[:len(bracketStack)-1]
+					if len(bracketStack) == 0 {
+						// Found matching bracket - update range
+						symbolRange.End.Line = lineNum
+						symbolRange.End.Character = uint32(pos + 1)
+						return symbolRange
+					}
+				}
+			}
+		}
+	}
+	return symbolRange
+}
+
 // GetLineRangesToDisplay determines which lines should be displayed for a set of locations
 func GetLineRangesToDisplay(ctx context.Context, client *lsp.Client, locations []protocol.Location, totalLines int, contextLines int) (map[int]bool, error) {
 	// Set to track which lines need to be displayed
@@ -181,3 +232,80 @@ func GetLineRangesToDisplay(ctx context.Context, client *lsp.Client, locations [
 
 	return linesToShow, nil
 }
+
+// resolvePositions returns the positions to query for a symbol, identified either by name
+// or, if symbolName is empty, by a 1-indexed line and column in filePath. Files are opened
+// in the language server as needed.
+func resolvePositions(ctx context.Context, client *lsp.Client, symbolName, filePath string, line, column int) ([]protocol.TextDocumentPositionParams, error) {
+	if symbolName == "" {
+		if err := client.OpenFile(ctx, filePath); err != nil {
+			return nil, fmt.Errorf("could not open file: %v", err)
+		}
+		return []protocol.TextDocumentPositionParams{textDocumentPosition(filePath, line, column)}, nil
+	}
+
+	symbols, err := FindSymbols(ctx, client, symbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []protocol.TextDocumentPositionParams
+	for _, symbol := range symbols {
+		loc := symbol.GetLocation()
+		if err := client.OpenFile(ctx, loc.URI.Path()); err != nil {
+			toolsLogger.Error("Error opening file: %v", err)
+			continue
+		}
+		positions = append(positions, protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+			Position:     loc.Range.Start,
+		})
+	}
+	return positions, nil
+}
+
+// withUnsavedText inserts text at a 1-indexed position in the server's copy of a file without
+// writing it to disk, then calls fn with the position just after the inserted text. The server's
+// copy is restored from disk afterwards. If text is empty, fn is called with the position as is.
+func withUnsavedText(ctx context.Context, client *lsp.Client, filePath string, line, column int, text string, fn func(protocol.TextDocumentPositionParams) error) error {
+	if err := client.OpenFile(ctx, filePath); err != nil {
+		return fmt.Errorf("could not open file: %v", err)
+	}
+
+	if text == "" {
+		return fn(textDocumentPosition(filePath, line, column))
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %v", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if line < 1 || line > len(lines) {
+		return fmt.Errorf("line %d is out of range, the file has %d lines", line, len(lines))
+	}
+	lineText := lines[line-1]
+	if column < 1 || column > len(lineText)+1 {
+		return fmt.Errorf("column %d is out of range, line %d has %d characters", column, line, len(lineText))
+	}
+	lines[line-1] = lineText[:column-1] + text + lineText[column-1:]
+
+	// Move the cursor to the end of the inserted text
+	newLine := line + strings.Count(text, "\n")
+	newColumn := column + len(text)
+	if idx := strings.LastIndex(text, "\n"); idx >= 0 {
+		newColumn = len(text) - idx
+	}
+
+	if err := client.NotifyContent(ctx, filePath, strings.Join(lines, "\n")); err != nil {
+		return fmt.Errorf("failed to send unsaved text: %v", err)
+	}
+	defer func() {
+		if err := client.NotifyChange(ctx, filePath); err != nil {
+			toolsLogger.Error("Failed to restore file content after unsaved text: %v", err)
+		}
+	}()
+
+	return fn(textDocumentPosition(filePath, newLine, newColumn))
+}
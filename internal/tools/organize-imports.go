@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// OrganizeImports adds missing imports, removes unused ones and sorts them using the
+// language server's source.organizeImports code action for the whole file
+func OrganizeImports(ctx context.Context, client *lsp.Client, filePath string) (string, error) {
+	before, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	if !client.SupportsCodeActionKind(protocol.SourceOrganizeImports) {
+		return "", fmt.Errorf("the language server does not support organizing imports")
+	}
+
+	actions, _, err := requestCodeActions(ctx, client, CodeActionRange{
+		FilePath:  filePath,
+		StartLine: 1,
+		EndLine:   strings.Count(string(before), "\n") + 1,
+		Kinds:     []string{string(protocol.SourceOrganizeImports)},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Servers may return more specific kinds such as source.organizeImports.ruff, and
+	// not all of them honor the kinds filter
+	var action *protocol.CodeAction
+	for i := range actions {
+		kind := actions[i].Kind
+		if actions[i].Disabled != nil {
+			continue
+		}
+		if kind == protocol.SourceOrganizeImports || strings.HasPrefix(string(kind), string(protocol.SourceOrganizeImports)+".") {
+			action = &actions[i]
+			break
+		}
+	}
+
+	if action == nil {
+		return fmt.Sprintf("%s\nImports are already organized, no organize imports action available", filePath), nil
+	}
+
+	if _, err := applyCodeAction(ctx, client, *action); err != nil {
+		return "", err
+	}
+
+	after, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read updated file: %v", err)
+	}
+
+	removed, added := countChangedLines(string(before), string(after))
+	if removed == 0 && added == 0 {
+		return fmt.Sprintf("%s\nImports are already organized, no changes made", filePath), nil
+	}
+
+	return fmt.Sprintf("%s\nApplied code action: %s\n%s removed, %s added.", filePath, action.Title, pluralLines(removed), pluralLines(added)), nil
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/isaacphi/mcp-language-server/internal/lsp"
@@ -13,13 +12,7 @@ import (
 )
 
 func FindReferences(ctx context.Context, client *lsp.Client, symbolName string) (string, error) {
-	// Get context lines from environment variable
-	contextLines := 5
-	if envLines := os.Getenv("LSP_CONTEXT_LINES"); envLines != "" {
-		if val, err := strconv.Atoi(envLines); err == nil && val >= 0 {
-			contextLines = val
-		}
-	}
+	contextLines := getContextLines(5)
 
 	// First get the symbol location like ReadDefinition does
 	symbolResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
@@ -77,75 +70,114 @@ func FindReferences(ctx context.Context, client *lsp.Client, symbolName string)
 			return "", fmt.Errorf("failed to get references: %v", err)
 		}
 
-		// Group references by file
-		refsByFile := make(map[protocol.DocumentUri][]protocol.Location)
-		for _, ref := range refs {
-			refsByFile[ref.URI] = append(refsByFile[ref.URI], ref)
-		}
-
-		// Get sorted list of URIs
-		uris := make([]string, 0, len(refsByFile))
-		for uri := range refsByFile {
-			uris = append(uris, string(uri))
-		}
-		sort.Strings(uris)
-
-		// Process each file's references in sorted order
-		for _, uriStr := range uris {
-			uri := protocol.DocumentUri(uriStr)
-			fileRefs := refsByFile[uri]
-			filePath := strings.TrimPrefix(uriStr, "file://")
-
-			// Format file header
-			fileInfo := fmt.Sprintf("---\n\n%s\nReferences in File: %d\n",
-				filePath,
-				len(fileRefs),
-			)
-
-			// Format locations with context
-			fileContent, err := os.ReadFile(filePath)
-			if err != nil {
-				// Log error but continue with other files
-				allReferences = append(allReferences, fileInfo+"\nError reading file: "+err.Error())
-				continue
-			}
-
-			lines := strings.Split(string(fileContent), "\n")
+		allReferences = append(allReferences, formatReferences(ctx, client, refs, contextLines)...)
+	}
 
-			// Track reference locations for header display
-			var locStrings []string
-			for _, ref := range fileRefs {
-				locStr := fmt.Sprintf("L%d:C%d",
-					ref.Range.Start.Line+1,
-					ref.Range.Start.Character+1)
-				locStrings = append(locStrings, locStr)
-			}
+	if len(allReferences) == 0 {
+		return fmt.Sprintf("No references found for symbol: %s", symbolName), nil
+	}
 
-			// Collect lines to display using the utility function
-			linesToShow, err := GetLineRangesToDisplay(ctx, client, fileRefs, len(lines), contextLines)
-			if err != nil {
-				// Log error but continue with other files
-				continue
-			}
+	return strings.Join(allReferences, "\n"), nil
+}
 
-			// Convert to line ranges using the utility function
-			lineRanges := ConvertLinesToRanges(linesToShow, len(lines))
+// FindReferencesAtPosition finds all usages of the symbol at the specified position. Unlike
+// FindReferences, it works for locals, parameters, fields and other symbols that are not
+// reported by workspace/symbol.
+func FindReferencesAtPosition(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	contextLines := getContextLines(5)
 
-			// Format with locations in header
-			formattedOutput := fileInfo
-			if len(locStrings) > 0 {
-				formattedOutput += "At: " + strings.Join(locStrings, ", ") + "\n"
-			}
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
 
-			// Format the content with ranges
-			formattedOutput += "\n" + FormatLinesWithRanges(lines, lineRanges)
-			allReferences = append(allReferences, formattedOutput)
-		}
+	refs, err := client.References(ctx, protocol.ReferenceParams{
+		TextDocumentPositionParams: textDocumentPosition(filePath, line, column),
+		Context: protocol.ReferenceContext{
+			IncludeDeclaration: false,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get references: %v", err)
 	}
 
+	allReferences := formatReferences(ctx, client, refs, contextLines)
 	if len(allReferences) == 0 {
-		return fmt.Sprintf("No references found for symbol: %s", symbolName), nil
+		return fmt.Sprintf("No references found at %s L%d:C%d", filePath, line, column), nil
 	}
 
 	return strings.Join(allReferences, "\n"), nil
 }
+
+// formatReferences groups reference locations by file and formats each file with the
+// reference lines and their surrounding context
+func formatReferences(ctx context.Context, client *lsp.Client, refs []protocol.Location, contextLines int) []string {
+	var allReferences []string
+
+	// Group references by file
+	refsByFile := make(map[protocol.DocumentUri][]protocol.Location)
+	for _, ref := range refs {
+		refsByFile[ref.URI] = append(refsByFile[ref.URI], ref)
+	}
+
+	// Get sorted list of URIs
+	uris := make([]string, 0, len(refsByFile))
+	for uri := range refsByFile {
+		uris = append(uris, string(uri))
+	}
+	sort.Strings(uris)
+
+	// Process each file's references in sorted order
+	for _, uriStr := range uris {
+		uri := protocol.DocumentUri(uriStr)
+		fileRefs := refsByFile[uri]
+		filePath := strings.TrimPrefix(uriStr, "file://")
+
+		// Format file header
+		fileInfo := fmt.Sprintf("---\n\n%s\nReferences in File: %d\n",
+			filePath,
+			len(fileRefs),
+		)
+
+		// Format locations with context
+		fileContent, err := os.ReadFile(filePath)
+		if err != nil {
+			// Log error but continue with other files
+			allReferences = append(allReferences, fileInfo+"\nError reading file: "+err.Error())
+			continue
+		}
+
+		lines := strings.Split(string(fileContent), "\n")
+
+		// Track reference locations for header display
+		var locStrings []string
+		for _, ref := range fileRefs {
+			locStr := fmt.Sprintf("L%d:C%d",
+				ref.Range.Start.Line+1,
+				ref.Range.Start.Character+1)
+			locStrings = append(locStrings, locStr)
+		}
+
+		// Collect lines to display using the utility function
+		linesToShow, err := GetLineRangesToDisplay(ctx, client, fileRefs, len(lines), contextLines)
+		if err != nil {
+			// Log error but continue with other files
+			continue
+		}
+
+		// Convert to line ranges using the utility function
+		lineRanges := ConvertLinesToRanges(linesToShow, len(lines))
+
+		// Format with locations in header
+		formattedOutput := fileInfo
+		if len(locStrings) > 0 {
+			formattedOutput += "At: " + strings.Join(locStrings, ", ") + "\n"
+		}
+
+		// Format the content with ranges
+		formattedOutput += "\n" + FormatLinesWithRanges(lines, lineRanges)
+		allReferences = append(allReferences, formattedOutput)
+	}
+
+	return allReferences
+}
@@ -12,8 +12,11 @@ import (
 )
 
 // RenameSymbol renames a symbol (variable, function, class, etc.) at the specified position
-// It uses the LSP rename functionality to handle all references across files
-func RenameSymbol(ctx context.Context, client *lsp.Client, filePath string, line, column int, newName string) (string, error) {
+// It uses the LSP rename functionality to handle all references across files. In dry run
+// mode the position is validated with prepare rename and a diff of the changes is returned
+// instead of writing them. If showDiagnostics is set, the result also lists the diagnostics
+// introduced or resolved in the changed files.
+func RenameSymbol(ctx context.Context, client *lsp.Client, filePath string, line, column int, newName string, dryRun, showDiagnostics bool) (string, error) {
 	// Open the file if not already open
 	err := client.OpenFile(ctx, filePath)
 	if err != nil {
@@ -36,8 +39,15 @@ func RenameSymbol(ctx context.Context, client *lsp.Client, filePath string, line
 		NewName:  newName,
 	}
 
-	// Skip the PrepareRename check as it might not be supported by all language servers
-	// Execute the rename directly
+	// Only check with PrepareRename in dry run mode, as it might not be supported by all
+	// language servers
+	var prepareSummary string
+	if dryRun {
+		prepareSummary, err = prepareRename(ctx, client, filePath, line, column)
+		if err != nil {
+			return "", err
+		}
+	}
 
 	// Execute the rename operation
 	workspaceEdit, err := client.Rename(ctx, params)
@@ -112,8 +122,29 @@ func RenameSymbol(ctx context.Context, client *lsp.Client, filePath string, line
 		locationsBuilder.WriteString(fmt.Sprintf("%s: %s\n", change.URI, change.Locations))
 	}
 
+	if dryRun {
+		if fileCount == 0 || changeCount == 0 {
+			return fmt.Sprintf("Dry run, no files were changed.\n%sRename would change 0 occurrences.", prepareSummary), nil
+		}
+		diff, err := utilities.PreviewWorkspaceEdit(workspaceEdit)
+		if err != nil {
+			return "", fmt.Errorf("failed to preview changes: %v", err)
+		}
+		return fmt.Sprintf("Dry run, no files were changed.\n%sRenaming to '%s' would update %d occurrences across %d files:\n%s\n%s",
+			prepareSummary, newName, changeCount, fileCount, locationsBuilder.String(), diff), nil
+	}
+
+	var diagnosticsBefore map[protocol.DocumentUri][]protocol.Diagnostic
+	if showDiagnostics {
+		var paths []string
+		for _, change := range allChanges {
+			paths = append(paths, protocol.DocumentUri(change.URI).Path())
+		}
+		diagnosticsBefore = captureDiagnostics(ctx, client, paths)
+	}
+
 	// Apply the workspace edit to files:workspaceEdit
-	if err := utilities.ApplyWorkspaceEdit(workspaceEdit); err != nil {
+	if err := utilities.ApplyWorkspaceEdit(workspaceEdit, fmt.Sprintf("Rename symbol at %s:%d:%d to %s", filePath, line, column, newName)); err != nil {
 		return "", fmt.Errorf("failed to apply changes: %v", err)
 	}
 
@@ -122,6 +153,51 @@ func RenameSymbol(ctx context.Context, client *lsp.Client, filePath string, line
 	}
 
 	// Generate a summary of changes made
-	return fmt.Sprintf("Successfully renamed symbol to '%s'.\nUpdated %d occurrences across %d files:\n%s",
-		newName, changeCount, fileCount, locationsBuilder.String()), nil
+	result := fmt.Sprintf("Successfully renamed symbol to '%s'.\nUpdated %d occurrences across %d files:\n%s",
+		newName, changeCount, fileCount, locationsBuilder.String())
+	if showDiagnostics {
+		result += diagnosticsDelta(ctx, client, diagnosticsBefore)
+	}
+	return result, nil
+}
+
+// prepareRename asks the server whether the symbol at a position can be renamed and
+// describes the range that would be renamed. Servers that did not announce prepare
+// rename support are reported as such rather than failing.
+func prepareRename(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	if !client.SupportsPrepareRename() {
+		return "Prepare rename is not supported by the language server, the position was not validated.\n", nil
+	}
+
+	result, err := client.PrepareRename(ctx, protocol.PrepareRenameParams{
+		TextDocumentPositionParams: textDocumentPosition(filePath, line, column),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot rename symbol at L%d:C%d: %v", line, column, err)
+	}
+
+	var rng protocol.Range
+	var placeholder string
+	switch v := result.Value.(type) {
+	case nil:
+		return "", fmt.Errorf("cannot rename symbol at L%d:C%d: the language server reports there is no symbol that can be renamed at this position", line, column)
+	case protocol.PrepareRenamePlaceholder:
+		rng = v.Range
+		placeholder = v.Placeholder
+	case protocol.Range:
+		rng = v
+		placeholder, err = ExtractTextFromLocation(protocol.Location{
+			URI:   protocol.DocumentUri("file://" + filePath),
+			Range: rng,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to read symbol text: %v", err)
+		}
+	case protocol.PrepareRenameDefaultBehavior:
+		return fmt.Sprintf("Symbol at L%d:C%d can be renamed, the language server did not report its range.\n", line, column), nil
+	default:
+		return "", fmt.Errorf("unexpected prepare rename result type: %T", v)
+	}
+
+	return fmt.Sprintf("Would rename '%s' at %s\n", placeholder, formatRange(rng)), nil
 }
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetSignatureHelp returns the signatures of the function call at a position, with their
+// parameters and the parameter the cursor is on. If text is not empty, it is inserted at the
+// position first, without saving the file, and signature help is requested after it.
+func GetSignatureHelp(ctx context.Context, client *lsp.Client, filePath string, line, column int, text string) (string, error) {
+	var help signatureHelp
+	err := withUnsavedText(ctx, client, filePath, line, column, text, func(pos protocol.TextDocumentPositionParams) error {
+		err := client.Call(ctx, "textDocument/signatureHelp", protocol.SignatureHelpParams{
+			TextDocumentPositionParams: pos,
+		}, &help)
+		if err != nil {
+			return fmt.Errorf("failed to get signature help: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(help.Signatures) == 0 {
+		return fmt.Sprintf("%s\nNo signature help available at L%d:C%d", filePath, line, column), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s\nSignature help at L%d:C%d\n\n", filePath, line, column))
+
+	for i, sig := range help.Signatures {
+		output.WriteString(fmt.Sprintf("Signature %d of %d", i+1, len(help.Signatures)))
+		if len(help.Signatures) > 1 && uint32(i) == help.ActiveSignature {
+			output.WriteString(" (active)")
+		}
+		output.WriteString(fmt.Sprintf(":\n%s\n", sig.Label))
+
+		// The active parameter of a signature takes precedence over the one for the whole result
+		activeParameter := help.ActiveParameter
+		if sig.ActiveParameter != nil {
+			activeParameter = *sig.ActiveParameter
+		}
+
+		if len(sig.Parameters) > 0 {
+			output.WriteString("Parameters:\n")
+			for j, param := range sig.Parameters {
+				output.WriteString(fmt.Sprintf("  %d. %s", j+1, parameterLabel(sig.Label, param.Label)))
+				if uint32(j) == activeParameter {
+					output.WriteString(" (active)")
+				}
+				if param.Documentation != nil {
+					if doc := documentationText(param.Documentation.Value); doc != "" {
+						output.WriteString(" - " + doc)
+					}
+				}
+				output.WriteString("\n")
+			}
+		}
+
+		if sig.Documentation != nil {
+			if doc := documentationText(sig.Documentation.Value); doc != "" {
+				output.WriteString(fmt.Sprintf("Documentation:\n%s\n", doc))
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String(), nil
+}
+
+// signatureHelp is a protocol.SignatureHelp whose signatures tell an active parameter of 0
+// apart from none, which the generated type can't
+type signatureHelp struct {
+	protocol.SignatureHelp
+	Signatures []signatureInformation `json:"signatures"`
+}
+
+// signatureInformation is a protocol.SignatureInformation with an optional active parameter
+type signatureInformation struct {
+	protocol.SignatureInformation
+	ActiveParameter *uint32 `json:"activeParameter,omitempty"`
+}
+
+// parameterLabel returns the text of a parameter label, which is either a string or
+// UTF-16 offsets into the signature label
+func parameterLabel(signatureLabel string, label protocol.Or_ParameterInformation_label) string {
+	switch v := label.Value.(type) {
+	case string:
+		return v
+	case protocol.Tuple_ParameterInformation_label_Item1:
+		start, startOK := utf16OffsetToByte(signatureLabel, v.Fld0)
+		end, endOK := utf16OffsetToByte(signatureLabel, v.Fld1)
+		if startOK && endOK && start <= end {
+			return signatureLabel[start:end]
+		}
+	}
+	return ""
+}
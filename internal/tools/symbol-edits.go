@@ -0,0 +1,364 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// ReplaceSymbol replaces the full source of a function, method, type or other symbol, as
+// shown by the definition tool, with newText. If newText starts with a comment it also
+// replaces the symbol's doc comment, otherwise the doc comment is kept.
+func ReplaceSymbol(ctx context.Context, client *lsp.Client, symbolName, filePath, newText string) (string, error) {
+	loc, lines, err := locateSymbol(ctx, client, symbolName, filePath)
+	if err != nil {
+		return "", err
+	}
+	path := loc.URI.Path()
+	startLine := int(loc.Range.Start.Line)
+	endLine := int(loc.Range.End.Line)
+
+	newText = strings.TrimRight(strings.ReplaceAll(newText, "\r\n", "\n"), "\n")
+	comments := commentSyntaxFor(loc.URI)
+	if comments.isCommentLine(strings.SplitN(newText, "\n", 2)[0]) {
+		startLine = comments.docCommentStart(lines, startLine)
+	}
+
+	// Replace whole lines, so that the replacement doesn't need to match the columns
+	edit := protocol.TextEdit{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: uint32(startLine)},
+			End:   protocol.Position{Line: uint32(endLine), Character: uint32(len(lines[endLine]))},
+		},
+		NewText: newText,
+	}
+	if err := applySymbolEdit(loc.URI, edit, fmt.Sprintf("Replace %s in %s", symbolName, path)); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s\nReplaced %s at L%d-L%d with %s:\n\n%s",
+		path, symbolName, startLine+1, endLine+1, pluralLines(strings.Count(newText, "\n")+1),
+		addLineNumbers(newText, startLine+1)), nil
+}
+
+// InsertAfterSymbol inserts newText after the full source of a symbol, or before it and its
+// doc comment if before is set, separated from it by a blank line
+func InsertAfterSymbol(ctx context.Context, client *lsp.Client, symbolName, filePath, newText string, before bool) (string, error) {
+	loc, lines, err := locateSymbol(ctx, client, symbolName, filePath)
+	if err != nil {
+		return "", err
+	}
+	path := loc.URI.Path()
+
+	newText = strings.TrimRight(strings.ReplaceAll(newText, "\r\n", "\n"), "\n")
+	var edit protocol.TextEdit
+	var insertedLine int
+	var where string
+	if before {
+		// Keep doc comments attached to the symbol they document
+		startLine := commentSyntaxFor(loc.URI).docCommentStart(lines, int(loc.Range.Start.Line))
+		pos := protocol.Position{Line: uint32(startLine)}
+		edit = protocol.TextEdit{
+			Range:   protocol.Range{Start: pos, End: pos},
+			NewText: newText + "\n\n",
+		}
+		insertedLine = startLine + 1
+		where = "before"
+	} else {
+		endLine := int(loc.Range.End.Line)
+		pos := protocol.Position{Line: uint32(endLine), Character: uint32(len(lines[endLine]))}
+		edit = protocol.TextEdit{
+			Range:   protocol.Range{Start: pos, End: pos},
+			NewText: "\n\n" + newText,
+		}
+		insertedLine = endLine + 3
+		where = "after"
+	}
+
+	if err := applySymbolEdit(loc.URI, edit, fmt.Sprintf("Insert %s %s in %s", where, symbolName, path)); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s\nInserted %s %s %s:\n\n%s",
+		path, pluralLines(strings.Count(newText, "\n")+1), where, symbolName,
+		addLineNumbers(newText, insertedLine)), nil
+}
+
+// locateSymbol finds the full range of the only symbol matching symbolName, optionally
+// restricted to a file, and returns it with the lines of its file without line endings
+func locateSymbol(ctx context.Context, client *lsp.Client, symbolName, filePath string) (protocol.Location, []string, error) {
+	if filePath != "" {
+		// Match the absolute paths of the symbols' locations. The server runs in the
+		// workspace, so relative paths resolve against it.
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			return protocol.Location{}, nil, fmt.Errorf("invalid file path: %v", err)
+		}
+		filePath = absPath
+
+		// Symbols in files the server hasn't loaded may be missing from workspace/symbol
+		if err := client.OpenFile(ctx, filePath); err != nil {
+			return protocol.Location{}, nil, fmt.Errorf("could not open file: %v", err)
+		}
+	}
+
+	symbols, err := FindSymbols(ctx, client, symbolName)
+	if err != nil {
+		return protocol.Location{}, nil, err
+	}
+
+	var matches []protocol.Location
+	var descriptions []string
+	for _, symbol := range symbols {
+		loc := symbol.GetLocation()
+		if filePath != "" && !samePath(loc.URI.Path(), filePath) {
+			continue
+		}
+
+		if err := client.OpenFile(ctx, loc.URI.Path()); err != nil {
+			toolsLogger.Error("Error opening file: %v", err)
+			continue
+		}
+
+		fullLoc, err := symbolLocation(ctx, client, loc)
+		if err != nil {
+			toolsLogger.Debug("Could not find the full range of %s: %v", symbol.GetName(), err)
+			continue
+		}
+
+		duplicate := false
+		for _, match := range matches {
+			if match.URI == fullLoc.URI && match.Range == fullLoc.Range {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+
+		matches = append(matches, fullLoc)
+		descriptions = append(descriptions, fmt.Sprintf("%s L%d-L%d (%s)",
+			fullLoc.URI.Path(), fullLoc.Range.Start.Line+1, fullLoc.Range.End.Line+1, symbol.GetName()))
+	}
+
+	switch len(matches) {
+	case 0:
+		if filePath != "" {
+			return protocol.Location{}, nil, fmt.Errorf("symbol %s not found in %s", symbolName, filePath)
+		}
+		return protocol.Location{}, nil, fmt.Errorf("symbol %s not found", symbolName)
+	case 1:
+	default:
+		return protocol.Location{}, nil, fmt.Errorf("symbol %s is ambiguous, it matches %d symbols. Use a qualified name such as Type.Method or pass filePath:\n%s",
+			symbolName, len(matches), strings.Join(descriptions, "\n"))
+	}
+
+	content, err := os.ReadFile(matches[0].URI.Path())
+	if err != nil {
+		return protocol.Location{}, nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	if int(matches[0].Range.End.Line) >= len(lines) {
+		return protocol.Location{}, nil, fmt.Errorf("symbol range is out of range of the file")
+	}
+
+	return matches[0], lines, nil
+}
+
+// symbolLocation returns the full range of the symbol named at loc, in whole lines. Unlike
+// GetFullDefinition, which returns the outermost symbol containing loc, it returns the
+// innermost one so that a method is located on its own rather than with its class.
+func symbolLocation(ctx context.Context, client *lsp.Client, loc protocol.Location) (protocol.Location, error) {
+	symResult, err := client.DocumentSymbol(ctx, protocol.DocumentSymbolParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+	})
+	if err != nil {
+		return protocol.Location{}, fmt.Errorf("failed to get document symbols: %w", err)
+	}
+	symbols, err := symResult.Results()
+	if err != nil {
+		return protocol.Location{}, fmt.Errorf("failed to process document symbols: %w", err)
+	}
+
+	symbolRange, ok := innermostSymbolRange(symbols, loc.Range.Start)
+	if !ok {
+		return protocol.Location{}, fmt.Errorf("symbol not found")
+	}
+
+	content, err := os.ReadFile(loc.URI.Path())
+	if err != nil {
+		return protocol.Location{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	if int(symbolRange.End.Line) >= len(lines) {
+		return protocol.Location{}, fmt.Errorf("line number out of range")
+	}
+
+	symbolRange.Start.Character = 0
+	return protocol.Location{URI: loc.URI, Range: extendToClosingBracket(lines, symbolRange)}, nil
+}
+
+// innermostSymbolRange returns the range of the most deeply nested symbol whose name is at
+// pos. Servers that return flat SymbolInformation have no selection ranges, so for those,
+// and if no name is at pos, it falls back to the smallest symbol containing pos.
+func innermostSymbolRange(symbols []protocol.DocumentSymbolResult, pos protocol.Position) (protocol.Range, bool) {
+	var named, containing *protocol.Range
+	var search func(symbols []protocol.DocumentSymbolResult)
+	search = func(symbols []protocol.DocumentSymbolResult) {
+		for _, sym := range symbols {
+			rng := sym.GetRange()
+			if !containsPosition(rng, pos) {
+				continue
+			}
+			if containing == nil || containsRange(*containing, rng) {
+				containing = &rng
+			}
+
+			ds, ok := sym.(*protocol.DocumentSymbol)
+			if !ok {
+				continue
+			}
+			if containsPosition(ds.SelectionRange, pos) {
+				named = &rng
+			}
+			children := make([]protocol.DocumentSymbolResult, len(ds.Children))
+			for i := range ds.Children {
+				children[i] = &ds.Children[i]
+			}
+			search(children)
+		}
+	}
+	search(symbols)
+
+	switch {
+	case named != nil:
+		return *named, true
+	case containing != nil:
+		return *containing, true
+	default:
+		return protocol.Range{}, false
+	}
+}
+
+// containsRange reports whether inner lies within outer
+func containsRange(outer, inner protocol.Range) bool {
+	return !positionBefore(inner.Start, outer.Start) && !positionBefore(outer.End, inner.End)
+}
+
+// positionBefore reports whether a comes before b
+func positionBefore(a, b protocol.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// samePath reports whether two absolute paths refer to the same file, also if one of them
+// goes through a symlink, as with /tmp on macOS
+func samePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+	resolvedA, err := filepath.EvalSymlinks(a)
+	if err != nil {
+		return false
+	}
+	resolvedB, err := filepath.EvalSymlinks(b)
+	if err != nil {
+		return false
+	}
+	return resolvedA == resolvedB
+}
+
+// applySymbolEdit applies a single edit through the journaled workspace edit path
+func applySymbolEdit(uri protocol.DocumentUri, edit protocol.TextEdit, description string) error {
+	workspaceEdit := protocol.WorkspaceEdit{
+		Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+			uri: {edit},
+		},
+	}
+	if err := utilities.ApplyWorkspaceEdit(workspaceEdit, description); err != nil {
+		return fmt.Errorf("failed to apply edit: %v", err)
+	}
+	return nil
+}
+
+// commentSyntax describes how comments are written in a language
+type commentSyntax struct {
+	// line holds the tokens that start a line comment
+	line []string
+	// block is set for languages with /* */ block comments
+	block bool
+}
+
+// commentSyntaxFor returns the comment syntax of a file's language. Languages that aren't
+// listed use C style comments, so that lines such as #include or #[derive] aren't taken
+// for comments.
+func commentSyntaxFor(uri protocol.DocumentUri) commentSyntax {
+	switch lsp.DetectLanguageID(uri.Path()) {
+	case protocol.LangPython, protocol.LangRuby, protocol.LangPerl, protocol.LangPerl6,
+		protocol.LangShellScript, protocol.LangPowershell, protocol.LangR, protocol.LangElixir,
+		protocol.LangCoffeescript, protocol.LangMakefile, protocol.LangDockerfile, protocol.LangYAML:
+		return commentSyntax{line: []string{"#"}}
+	case protocol.LangSQL, protocol.LangLua, protocol.LangHaskell:
+		return commentSyntax{line: []string{"--"}}
+	case protocol.LangClojure:
+		return commentSyntax{line: []string{";"}}
+	case protocol.LangErlang, protocol.LangTeX, protocol.LangLaTeX:
+		return commentSyntax{line: []string{"%"}}
+	case protocol.LangVisualBasic:
+		return commentSyntax{line: []string{"'"}}
+	default:
+		return commentSyntax{line: []string{"//"}, block: true}
+	}
+}
+
+// isCommentLine reports whether a line starts with a comment
+func (c commentSyntax) isCommentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if c.block && strings.HasPrefix(trimmed, "/*") {
+		return true
+	}
+	for _, prefix := range c.line {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// docCommentStart returns the first line of the comments directly above line, or line
+// itself if there are none
+func (c commentSyntax) docCommentStart(lines []string, line int) int {
+	for line > 0 {
+		prev := strings.TrimSpace(lines[line-1])
+		switch {
+		case c.isCommentLine(prev):
+			line--
+		case c.block && strings.HasSuffix(prev, "*/"):
+			// Only the first line of a block comment is recognizable as a comment, so
+			// walk up to it, unless the comment follows code on the same line
+			if strings.Contains(prev, "/*") {
+				return line
+			}
+			start := line - 2
+			for start >= 0 && !strings.HasPrefix(strings.TrimSpace(lines[start]), "/*") {
+				if strings.Contains(lines[start], "*/") {
+					return line
+				}
+				start--
+			}
+			if start < 0 {
+				return line
+			}
+			line = start
+		default:
+			return line
+		}
+	}
+	return line
+}
@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// ReadTypeDefinition returns the full declaration of the type of the symbol at the specified
+// position, e.g. the struct or class of a variable
+func ReadTypeDefinition(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	err := client.OpenFile(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
+	}
+
+	result, err := client.TypeDefinition(ctx, protocol.TypeDefinitionParams{
+		TextDocumentPositionParams: textDocumentPosition(filePath, line, column),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get type definition: %v", err)
+	}
+
+	locations, err := result.Locations()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse results: %v", err)
+	}
+
+	definitions := formatLocationDefinitions(ctx, client, locations)
+	if len(definitions) == 0 {
+		return fmt.Sprintf("No type definition found at %s L%d:C%d", filePath, line, column), nil
+	}
+
+	return strings.Join(definitions, ""), nil
+}
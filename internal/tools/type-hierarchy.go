@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetTypeHierarchy returns the supertypes and/or subtypes of a type, up to depth levels deep.
+// direction is one of "up", "down" or "both". The symbol is identified either by name or,
+// if symbolName is empty, by its position in a file.
+func GetTypeHierarchy(ctx context.Context, client *lsp.Client, symbolName, filePath string, line, column int, direction string, depth int) (string, error) {
+	var showSupertypes, showSubtypes bool
+	switch direction {
+	case "up":
+		showSupertypes = true
+	case "down":
+		showSubtypes = true
+	case "both", "":
+		showSupertypes = true
+		showSubtypes = true
+	default:
+		return "", fmt.Errorf("invalid direction: %s. Must be one of up, down or both", direction)
+	}
+
+	if depth < 1 {
+		depth = 1
+	}
+
+	positions, err := resolvePositions(ctx, client, symbolName, filePath, line, column)
+	if err != nil {
+		return "", err
+	}
+
+	var items []protocol.TypeHierarchyItem
+	for _, pos := range positions {
+		prepared, err := client.PrepareTypeHierarchy(ctx, protocol.TypeHierarchyPrepareParams{
+			TextDocumentPositionParams: pos,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to prepare type hierarchy: %v", err)
+		}
+		items = append(items, prepared...)
+	}
+
+	if len(items) == 0 {
+		if symbolName != "" {
+			return fmt.Sprintf("%s not found", symbolName), nil
+		}
+		return fmt.Sprintf("No type found at %s L%d:C%d", filePath, line, column), nil
+	}
+
+	var result strings.Builder
+	for _, item := range items {
+		result.WriteString("---\n\n")
+		result.WriteString(fmt.Sprintf("Type: %s\n", item.Name))
+		result.WriteString(fmt.Sprintf("Kind: %s\n", protocol.TableKindMap[item.Kind]))
+		if item.Detail != "" {
+			result.WriteString(fmt.Sprintf("Detail: %s\n", item.Detail))
+		}
+		result.WriteString(fmt.Sprintf("File: %s\n", strings.TrimPrefix(string(item.URI), "file://")))
+		result.WriteString(fmt.Sprintf("Range: %s\n", formatRange(item.Range)))
+
+		if showSupertypes {
+			result.WriteString("\nSupertypes:\n")
+			walker := &typeHierarchyWalker{client: client, supertypes: true, maxDepth: depth, visited: make(map[string]bool)}
+			if err := walker.walk(ctx, &result, item, 1); err != nil {
+				return "", err
+			}
+		}
+
+		if showSubtypes {
+			result.WriteString("\nSubtypes:\n")
+			walker := &typeHierarchyWalker{client: client, supertypes: false, maxDepth: depth, visited: make(map[string]bool)}
+			if err := walker.walk(ctx, &result, item, 1); err != nil {
+				return "", err
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
+// typeHierarchyWalker recursively expands type hierarchy items into an indented tree
+type typeHierarchyWalker struct {
+	client     *lsp.Client
+	supertypes bool
+	maxDepth   int
+	// Items on the current path, used to stop at cycles
+	visited map[string]bool
+}
+
+func (w *typeHierarchyWalker) walk(ctx context.Context, out *strings.Builder, item protocol.TypeHierarchyItem, level int) error {
+	key := typeHierarchyKey(item)
+	w.visited[key] = true
+	defer delete(w.visited, key)
+
+	var related []protocol.TypeHierarchyItem
+	var err error
+	if w.supertypes {
+		related, err = w.client.Supertypes(ctx, protocol.TypeHierarchySupertypesParams{Item: item})
+	} else {
+		related, err = w.client.Subtypes(ctx, protocol.TypeHierarchySubtypesParams{Item: item})
+	}
+
+	indent := strings.Repeat("  ", level-1)
+	if err != nil {
+		// Only fail the whole request if the root item cannot be expanded
+		if level == 1 {
+			return fmt.Errorf("failed to get type hierarchy: %v", err)
+		}
+		toolsLogger.Error("Failed to expand type hierarchy for %s: %v", item.Name, err)
+		out.WriteString(fmt.Sprintf("%s(error: %v)\n", indent, err))
+		return nil
+	}
+
+	if len(related) == 0 && level == 1 {
+		out.WriteString("None found\n")
+		return nil
+	}
+
+	for _, rel := range related {
+		out.WriteString(fmt.Sprintf("%s- %s (%s)", indent, rel.Name, protocol.TableKindMap[rel.Kind]))
+		if rel.Detail != "" {
+			out.WriteString(fmt.Sprintf(" in %s", rel.Detail))
+		}
+
+		cycle := w.visited[typeHierarchyKey(rel)]
+		if cycle {
+			out.WriteString(" (cycle)")
+		}
+		out.WriteString("\n")
+
+		out.WriteString(fmt.Sprintf("%s  %s %s\n",
+			indent,
+			strings.TrimPrefix(string(rel.URI), "file://"),
+			formatRange(rel.Range),
+		))
+
+		if !cycle && level < w.maxDepth {
+			if err := w.walk(ctx, out, rel, level+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func typeHierarchyKey(item protocol.TypeHierarchyItem) string {
+	return fmt.Sprintf("%s:%d:%d", item.URI, item.SelectionRange.Start.Line, item.SelectionRange.Start.Character)
+}
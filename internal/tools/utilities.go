@@ -3,11 +3,14 @@ package tools
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/pmezard/go-difflib/difflib"
 )
 
 func ExtractTextFromLocation(loc protocol.Location) (string, error) {
@@ -81,6 +84,40 @@ func containsPosition(r protocol.Range, p protocol.Position) bool {
 	return true
 }
 
+// getContextLines returns the number of context lines to show around a location,
+// overridden by the LSP_CONTEXT_LINES environment variable if set
+func getContextLines(defaultLines int) int {
+	if envLines := os.Getenv("LSP_CONTEXT_LINES"); envLines != "" {
+		if val, err := strconv.Atoi(envLines); err == nil && val >= 0 {
+			return val
+		}
+	}
+	return defaultLines
+}
+
+// textDocumentPosition converts a file path and 1-indexed line and column to LSP position params
+func textDocumentPosition(filePath string, line, column int) protocol.TextDocumentPositionParams {
+	return protocol.TextDocumentPositionParams{
+		TextDocument: protocol.TextDocumentIdentifier{
+			URI: protocol.DocumentUri("file://" + filePath),
+		},
+		Position: protocol.Position{
+			Line:      uint32(line - 1),
+			Character: uint32(column - 1),
+		},
+	}
+}
+
+// formatRange formats a range as 1-indexed lines and columns
+func formatRange(r protocol.Range) string {
+	return fmt.Sprintf("L%d:C%d - L%d:C%d",
+		r.Start.Line+1,
+		r.Start.Character+1,
+		r.End.Line+1,
+		r.End.Character+1,
+	)
+}
+
 // addLineNumbers adds line numbers to each line of text with proper padding, starting from startLine
 func addLineNumbers(text string, startLine int) string {
 	lines := strings.Split(text, "\n")
@@ -167,3 +204,128 @@ func FormatLinesWithRanges(lines []string, ranges []LineRange) string {
 
 	return result.String()
 }
+
+// validatePathGlob checks the syntax of a pattern for matchPathGlob, which otherwise
+// treats a malformed pattern as matching nothing
+func validatePathGlob(pattern string) error {
+	for _, segment := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if segment == "**" {
+			continue
+		}
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pathGlob %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchPathGlob reports whether a file path matches a glob pattern. Patterns use
+// filepath.Match syntax for each path segment, plus "**" to match any number of
+// segments. Patterns that are not absolute may match any trailing part of the path,
+// so "internal/**/*.go" matches "/home/user/project/internal/tools/hover.go".
+func matchPathGlob(pattern, path string) bool {
+	patternParts := strings.Split(filepath.ToSlash(pattern), "/")
+	pathParts := strings.Split(filepath.ToSlash(path), "/")
+
+	if filepath.IsAbs(pattern) {
+		return matchGlobSegments(patternParts, pathParts)
+	}
+	for i := range pathParts {
+		if matchGlobSegments(patternParts, pathParts[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGlobSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		// Try matching the rest of the pattern against every suffix of the path
+		for i := 0; i <= len(path); i++ {
+			if matchGlobSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, err := filepath.Match(pattern[0], path[0]); err != nil || !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], path[1:])
+}
+
+// countChangedLines compares two versions of a text line by line and returns the number
+// of lines removed from before and added in after. A modified line counts as both.
+func countChangedLines(before, after string) (removed, added int) {
+	// Auto junk is disabled so common lines like "}" still match in large files
+	matcher := difflib.NewMatcherWithJunk(strings.Split(before, "\n"), strings.Split(after, "\n"), false, nil)
+	for _, op := range matcher.GetOpCodes() {
+		switch op.Tag {
+		case 'r':
+			removed += op.I2 - op.I1
+			added += op.J2 - op.J1
+		case 'd':
+			removed += op.I2 - op.I1
+		case 'i':
+			added += op.J2 - op.J1
+		}
+	}
+	return removed, added
+}
+
+// pluralLines formats a count of lines, as in "1 line" or "3 lines"
+func pluralLines(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
+
+// documentationText extracts the text of a documentation value, which is either a plain
+// string or MarkupContent
+func documentationText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case protocol.MarkupContent:
+		return strings.TrimSpace(v.Value)
+	default:
+		return ""
+	}
+}
+
+// utf16OffsetToByte converts an offset in UTF-16 code units, which the protocol uses for
+// offsets into strings, to a byte offset in s. It reports false if the offset is past the
+// end of s or falls inside a character.
+func utf16OffsetToByte(s string, offset uint32) (int, bool) {
+	var units uint32
+	for i, r := range s {
+		if units == offset {
+			return i, true
+		}
+		if units > offset {
+			return 0, false
+		}
+		units += uint32(utf16.RuneLen(r))
+	}
+	if units == offset {
+		return len(s), true
+	}
+	return 0, false
+}
+
+// utf16Len returns the length of s in UTF-16 code units, as the protocol counts the
+// characters of a line
+func utf16Len(s string) uint32 {
+	var units uint32
+	for _, r := range s {
+		units += uint32(utf16.RuneLen(r))
+	}
+	return units
+}
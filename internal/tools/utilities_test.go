@@ -3,6 +3,7 @@ package tools
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -454,3 +455,307 @@ func TestFormatLinesWithRanges(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchPathGlob(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{
+			name:     "File name only",
+			pattern:  "*.go",
+			path:     "/project/internal/tools/hover.go",
+			expected: true,
+		},
+		{
+			name:     "File name mismatch",
+			pattern:  "*.go",
+			path:     "/project/README.md",
+			expected: false,
+		},
+		{
+			name:     "Relative directory with double star",
+			pattern:  "internal/**/*.go",
+			path:     "/project/internal/tools/hover.go",
+			expected: true,
+		},
+		{
+			name:     "Double star matches zero directories",
+			pattern:  "internal/**/*.go",
+			path:     "/project/internal/main.go",
+			expected: true,
+		},
+		{
+			name:     "Relative directory mismatch",
+			pattern:  "cmd/**",
+			path:     "/project/internal/tools/hover.go",
+			expected: false,
+		},
+		{
+			name:     "Absolute pattern",
+			pattern:  "/project/internal/**",
+			path:     "/project/internal/tools/hover.go",
+			expected: true,
+		},
+		{
+			name:     "Absolute pattern does not match suffix",
+			pattern:  "/internal/**",
+			path:     "/project/internal/tools/hover.go",
+			expected: false,
+		},
+		{
+			name:     "Segment wildcard does not cross directories",
+			pattern:  "internal/*.go",
+			path:     "/project/internal/tools/hover.go",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, matchPathGlob(tc.pattern, tc.path))
+		})
+	}
+}
+
+func TestValidatePathGlob(t *testing.T) {
+	for _, pattern := range []string{"*.go", "internal/**/*.go", "/project/**", "[a-c]*.py"} {
+		assert.NoError(t, validatePathGlob(pattern), pattern)
+	}
+	for _, pattern := range []string{"[", "internal/[a-/*.go", "**/\\"} {
+		assert.Error(t, validatePathGlob(pattern), pattern)
+	}
+}
+
+func TestCountChangedLines(t *testing.T) {
+	testCases := []struct {
+		name            string
+		before          string
+		after           string
+		expectedRemoved int
+		expectedAdded   int
+	}{
+		{
+			name:   "No changes",
+			before: "line1\nline2\n",
+			after:  "line1\nline2\n",
+		},
+		{
+			name:            "Modified line",
+			before:          "line1\nline2\nline3\n",
+			after:           "line1\nLINE2\nline3\n",
+			expectedRemoved: 1,
+			expectedAdded:   1,
+		},
+		{
+			name:          "Inserted lines",
+			before:        "line1\nline3\n",
+			after:         "line1\nline2a\nline2b\nline3\n",
+			expectedAdded: 2,
+		},
+		{
+			name:            "Deleted line",
+			before:          "line1\n\n\nline2\n",
+			after:           "line1\n\nline2\n",
+			expectedRemoved: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			removed, added := countChangedLines(tc.before, tc.after)
+			assert.Equal(t, tc.expectedRemoved, removed, "removed lines")
+			assert.Equal(t, tc.expectedAdded, added, "added lines")
+		})
+	}
+}
+
+func TestUTF16OffsetToByte(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		offset   uint32
+		expected int
+		ok       bool
+	}{
+		{name: "ASCII", text: "func(a int)", offset: 5, expected: 5, ok: true},
+		{name: "End of text", text: "abc", offset: 3, expected: 3, ok: true},
+		{name: "Past the end", text: "abc", offset: 4},
+		{name: "After a two byte character", text: "f(é int, b int)", offset: 9, expected: 10, ok: true},
+		{name: "After a surrogate pair", text: "f(𐐀 int, b int)", offset: 10, expected: 12, ok: true},
+		{name: "Inside a surrogate pair", text: "f(𐐀 int)", offset: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := utf16OffsetToByte(tc.text, tc.offset)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUTF16Len(t *testing.T) {
+	assert.Equal(t, uint32(0), utf16Len(""))
+	assert.Equal(t, uint32(3), utf16Len("abc"))
+	assert.Equal(t, uint32(5), utf16Len("héllo"))
+	assert.Equal(t, uint32(4), utf16Len("a 👋"))
+}
+
+func TestInnermostSymbolRange(t *testing.T) {
+	rng := func(startLine, startChar, endLine, endChar uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: startChar},
+			End:   protocol.Position{Line: endLine, Character: endChar},
+		}
+	}
+
+	// A class with two methods, as returned by pyright
+	class := &protocol.DocumentSymbol{
+		Name:           "CleanClass",
+		Range:          rng(0, 0, 9, 0),
+		SelectionRange: rng(0, 6, 0, 16),
+		Children: []protocol.DocumentSymbol{
+			{Name: "__init__", Range: rng(2, 4, 4, 0), SelectionRange: rng(2, 8, 2, 16)},
+			{Name: "get_name", Range: rng(5, 4, 8, 0), SelectionRange: rng(5, 8, 5, 16)},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		symbols  []protocol.DocumentSymbolResult
+		position protocol.Position
+		expected protocol.Range
+		found    bool
+	}{
+		{
+			name:     "Method name",
+			symbols:  []protocol.DocumentSymbolResult{class},
+			position: protocol.Position{Line: 5, Character: 8},
+			expected: rng(5, 4, 8, 0),
+			found:    true,
+		},
+		{
+			name:     "Class name",
+			symbols:  []protocol.DocumentSymbolResult{class},
+			position: protocol.Position{Line: 0, Character: 6},
+			expected: rng(0, 0, 9, 0),
+			found:    true,
+		},
+		{
+			name:     "Method body falls back to the smallest symbol",
+			symbols:  []protocol.DocumentSymbolResult{class},
+			position: protocol.Position{Line: 3, Character: 8},
+			expected: rng(2, 4, 4, 0),
+			found:    true,
+		},
+		{
+			name: "Flat symbol information",
+			symbols: []protocol.DocumentSymbolResult{
+				&protocol.SymbolInformation{Name: "CleanClass", Location: protocol.Location{Range: rng(0, 0, 9, 0)}},
+				&protocol.SymbolInformation{Name: "get_name", Location: protocol.Location{Range: rng(5, 4, 8, 0)}},
+			},
+			position: protocol.Position{Line: 5, Character: 8},
+			expected: rng(5, 4, 8, 0),
+			found:    true,
+		},
+		{
+			name:     "Outside all symbols",
+			symbols:  []protocol.DocumentSymbolResult{class},
+			position: protocol.Position{Line: 12, Character: 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, found := innermostSymbolRange(tc.symbols, tc.position)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestDocCommentStart(t *testing.T) {
+	testCases := []struct {
+		name     string
+		uri      protocol.DocumentUri
+		lines    []string
+		expected int
+	}{
+		{
+			name:     "Go line comments",
+			uri:      "file:///workspace/main.go",
+			lines:    []string{"}", "", "// Foo does foo", "// twice", "func Foo() {"},
+			expected: 2,
+		},
+		{
+			name:     "C block comment",
+			uri:      "file:///workspace/main.c",
+			lines:    []string{"#include <stdio.h>", "/**", " * foo does foo", " */", "int foo(void) {"},
+			expected: 1,
+		},
+		{
+			name:     "C include is not a comment",
+			uri:      "file:///workspace/main.c",
+			lines:    []string{"#include <stdio.h>", "#define N 1", "int foo(void) {"},
+			expected: 2,
+		},
+		{
+			name:     "C statements are not comments",
+			uri:      "file:///workspace/main.c",
+			lines:    []string{"void bar(int *ptr, int i) {", "\t*ptr = 1;", "\t--i;", "int foo(void) {"},
+			expected: 3,
+		},
+		{
+			name:     "C block comment after code",
+			uri:      "file:///workspace/main.c",
+			lines:    []string{"/* header */", "int x = 1; /* x */", "int foo(void) {"},
+			expected: 2,
+		},
+		{
+			name:     "Rust attribute is not a comment",
+			uri:      "file:///workspace/src/main.rs",
+			lines:    []string{"/// A point", "#[derive(Debug)]", "struct Point {"},
+			expected: 2,
+		},
+		{
+			name:     "Python comments",
+			uri:      "file:///workspace/main.py",
+			lines:    []string{"x = 1", "# foo does foo", "def foo():"},
+			expected: 1,
+		},
+		{
+			name:     "Python decorator is not a comment",
+			uri:      "file:///workspace/main.py",
+			lines:    []string{"# comment", "@staticmethod", "def foo():"},
+			expected: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := commentSyntaxFor(tc.uri).docCommentStart(tc.lines, len(tc.lines)-1)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestSamePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Skipf("Symlinks are not supported: %v", err)
+	}
+
+	assert.True(t, samePath(file, file))
+	assert.True(t, samePath(filepath.Join(link, "main.go"), file))
+	assert.False(t, samePath(filepath.Join(dir, "other.go"), file))
+}
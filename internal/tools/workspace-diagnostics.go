@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// WorkspaceDiagnosticsFilter selects the diagnostics listed by GetWorkspaceDiagnostics.
+// Empty fields match everything.
+type WorkspaceDiagnosticsFilter struct {
+	// MinSeverity is the least severe level to include: error, warning, info or hint
+	MinSeverity string
+	Source      string
+	Code        string
+	PathGlob    string
+}
+
+// GetWorkspaceDiagnostics lists the diagnostics of every file in the workspace that the
+// language server reported on, grouped per file with counts by severity. At most limit
+// diagnostics are listed, the counts include all of them.
+func GetWorkspaceDiagnostics(ctx context.Context, client *lsp.Client, filter WorkspaceDiagnosticsFilter, limit int) (string, error) {
+	maxSeverity := protocol.SeverityHint
+	if filter.MinSeverity != "" {
+		var ok bool
+		maxSeverity, ok = parseSeverity(filter.MinSeverity)
+		if !ok {
+			return "", fmt.Errorf("unknown severity %q, expected error, warning, info or hint", filter.MinSeverity)
+		}
+	}
+	if filter.PathGlob != "" {
+		if err := validatePathGlob(filter.PathGlob); err != nil {
+			return "", err
+		}
+	}
+
+	// Servers that support workspace pull diagnostics report on files that were never
+	// opened, the others publish what they know
+	if client.SupportsWorkspaceDiagnostics() {
+		if err := client.PullWorkspaceDiagnostics(ctx); err != nil {
+			toolsLogger.Debug("Failed to pull workspace diagnostics: %v", err)
+		}
+	}
+	// Open files may have changes the server hasn't checked yet
+	client.WaitForDiagnostics(ctx, client.OpenFileURIs()...)
+
+	type fileDiagnostics struct {
+		path        string
+		diagnostics []protocol.Diagnostic
+	}
+	var files []fileDiagnostics
+	var all []protocol.Diagnostic
+	for uri, diagnostics := range client.AllDiagnostics() {
+		path := uri.Path()
+		if filter.PathGlob != "" && !matchPathGlob(filter.PathGlob, path) {
+			continue
+		}
+
+		var matched []protocol.Diagnostic
+		for _, diag := range diagnostics {
+			// Servers may leave out the severity, which clients treat as an error
+			severity := diag.Severity
+			if severity == 0 {
+				severity = protocol.SeverityError
+			}
+			if severity > maxSeverity {
+				continue
+			}
+			if filter.Source != "" && !strings.EqualFold(diag.Source, filter.Source) {
+				continue
+			}
+			if filter.Code != "" && (diag.Code == nil || fmt.Sprint(diag.Code) != filter.Code) {
+				continue
+			}
+			matched = append(matched, diag)
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		sort.SliceStable(matched, func(i, j int) bool {
+			if matched[i].Range.Start.Line != matched[j].Range.Start.Line {
+				return matched[i].Range.Start.Line < matched[j].Range.Start.Line
+			}
+			return matched[i].Range.Start.Character < matched[j].Range.Start.Character
+		})
+		files = append(files, fileDiagnostics{path: path, diagnostics: matched})
+		all = append(all, matched...)
+	}
+
+	if len(files) == 0 {
+		return "No diagnostics found in the workspace", nil
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Diagnostics in workspace: %d in %d files (%s)\n",
+		len(all), len(files), countSeverities(all)))
+	if limit > 0 && len(all) > limit {
+		output.WriteString(fmt.Sprintf("Showing the first %d\n", limit))
+	}
+
+	listed := 0
+	for _, file := range files {
+		if limit > 0 && listed >= limit {
+			break
+		}
+		output.WriteString(fmt.Sprintf("\n%s\nDiagnostics in File: %d (%s)\n",
+			file.path, len(file.diagnostics), countSeverities(file.diagnostics)))
+		for _, diag := range file.diagnostics {
+			if limit > 0 && listed >= limit {
+				break
+			}
+			output.WriteString(formatDiagnostic(diag) + "\n")
+			listed++
+		}
+	}
+
+	return output.String(), nil
+}
+
+// parseSeverity parses a severity name as used in the diagnostics output
+func parseSeverity(name string) (protocol.DiagnosticSeverity, bool) {
+	switch strings.ToLower(name) {
+	case "error", "errors":
+		return protocol.SeverityError, true
+	case "warning", "warnings":
+		return protocol.SeverityWarning, true
+	case "info", "information":
+		return protocol.SeverityInformation, true
+	case "hint", "hints":
+		return protocol.SeverityHint, true
+	default:
+		return 0, false
+	}
+}
+
+// countSeverities summarizes diagnostics by severity, e.g. "2 errors, 1 warning"
+func countSeverities(diagnostics []protocol.Diagnostic) string {
+	counts := make(map[protocol.DiagnosticSeverity]int)
+	for _, diag := range diagnostics {
+		severity := diag.Severity
+		if severity == 0 {
+			severity = protocol.SeverityError
+		}
+		counts[severity]++
+	}
+
+	var parts []string
+	for _, severity := range []struct {
+		severity protocol.DiagnosticSeverity
+		name     string
+	}{
+		{protocol.SeverityError, "error"},
+		{protocol.SeverityWarning, "warning"},
+		{protocol.SeverityInformation, "info"},
+		{protocol.SeverityHint, "hint"},
+	} {
+		count := counts[severity.severity]
+		if count == 0 {
+			continue
+		}
+		name := severity.name
+		if count > 1 && name != "info" {
+			name += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", count, name))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// SearchWorkspaceSymbols searches the workspace for symbols matching query, in the order
+// ranked by the language server. Results can be filtered by symbol kind and by a glob
+// matched against the file path, and at most limit results are returned.
+func SearchWorkspaceSymbols(ctx context.Context, client *lsp.Client, query string, kinds []string, pathGlob string, limit int) (string, error) {
+	kindFilter, err := parseSymbolKinds(kinds)
+	if err != nil {
+		return "", err
+	}
+	if pathGlob != "" {
+		if err := validatePathGlob(pathGlob); err != nil {
+			return "", err
+		}
+	}
+
+	symResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
+		Query: query,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search workspace symbols: %v", err)
+	}
+
+	results, err := symResult.Results()
+	if err != nil {
+		return "", fmt.Errorf("failed to process workspace symbols: %v", err)
+	}
+
+	var output strings.Builder
+	matched := 0
+	for _, symbol := range results {
+		var kind protocol.SymbolKind
+		var container string
+		switch v := symbol.(type) {
+		case *protocol.SymbolInformation:
+			kind = v.Kind
+			container = v.ContainerName
+		case *protocol.WorkspaceSymbol:
+			kind = v.Kind
+			container = v.ContainerName
+		}
+
+		if len(kindFilter) > 0 && !kindFilter[kind] {
+			continue
+		}
+
+		loc := symbol.GetLocation()
+		path := strings.TrimPrefix(string(loc.URI), "file://")
+		if pathGlob != "" && !matchPathGlob(pathGlob, path) {
+			continue
+		}
+
+		matched++
+		if limit > 0 && matched > limit {
+			continue
+		}
+
+		output.WriteString(fmt.Sprintf("%d. %s (%s)", matched, symbol.GetName(), protocol.TableKindMap[kind]))
+		if container != "" {
+			output.WriteString(fmt.Sprintf(" in %s", container))
+		}
+		output.WriteString("\n")
+		output.WriteString(fmt.Sprintf("   %s %s\n", path, formatRange(loc.Range)))
+	}
+
+	if matched == 0 {
+		return fmt.Sprintf("No symbols found matching %q", query), nil
+	}
+
+	header := fmt.Sprintf("Found %d symbols matching %q", matched, query)
+	if limit > 0 && matched > limit {
+		header += fmt.Sprintf(" (showing first %d)", limit)
+	}
+
+	return header + "\n\n" + output.String(), nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/pmezard/go-difflib/difflib"
 )
 
 var (
@@ -20,16 +21,9 @@ var (
 	osRename    = os.Rename
 )
 
-// ApplyTextEdits applies a sequence of text edits to a file specified by URI
-func ApplyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
-	path := strings.TrimPrefix(string(uri), "file://")
-
-	// Read the file content
-	content, err := osReadFile(path)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
-	}
-
+// ApplyTextEditsToContent applies a sequence of text edits to the content of a file and
+// returns the new content, preserving its line ending style and trailing newline
+func ApplyTextEditsToContent(content []byte, edits []protocol.TextEdit) ([]byte, error) {
 	// Detect line ending style
 	var lineEnding string
 	if bytes.Contains(content, []byte("\r\n")) {
@@ -48,7 +42,7 @@ func ApplyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
 	for i, edit1 := range edits {
 		for j := i + 1; j < len(edits); j++ {
 			if RangesOverlap(edit1.Range, edits[j].Range) {
-				return fmt.Errorf("overlapping edits detected between edit %d and %d", i, j)
+				return nil, fmt.Errorf("overlapping edits detected between edit %d and %d", i, j)
 			}
 		}
 	}
@@ -67,7 +61,7 @@ func ApplyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
 	for _, edit := range sortedEdits {
 		newLines, err := ApplyTextEdit(lines, edit, lineEnding)
 		if err != nil {
-			return fmt.Errorf("failed to apply edit: %w", err)
+			return nil, fmt.Errorf("failed to apply edit: %w", err)
 		}
 		lines = newLines
 	}
@@ -86,11 +80,7 @@ func ApplyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
 		newContent.WriteString(lineEnding)
 	}
 
-	if err := osWriteFile(path, []byte(newContent.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	return []byte(newContent.String()), nil
 }
 
 // ApplyTextEdit applies a single text edit to a set of lines
@@ -170,85 +160,231 @@ func ApplyTextEdit(lines []string, edit protocol.TextEdit, lineEnding string) ([
 	return result, nil
 }
 
-// ApplyDocumentChange applies a DocumentChange (create/rename/delete operations)
-func ApplyDocumentChange(change protocol.DocumentChange) error {
-	if change.CreateFile != nil {
-		path := strings.TrimPrefix(string(change.CreateFile.URI), "file://")
-		if change.CreateFile.Options != nil {
-			if change.CreateFile.Options.Overwrite {
-				// Proceed with overwrite
-			} else if change.CreateFile.Options.IgnoreIfExists {
-				if _, err := osStat(path); err == nil {
-					return nil // File exists and we're ignoring it
-				}
+// ApplyWorkspaceEdit applies the given WorkspaceEdit to the filesystem. The edit is
+// applied as a whole or not at all: files are written through temporary files that are
+// renamed into place, and if any operation fails every file touched so far is restored.
+// Applied edits are recorded in the journal, if one is set, under the given description.
+func ApplyWorkspaceEdit(edit protocol.WorkspaceEdit, description string) error {
+	var tx editTransaction
+
+	err := func() error {
+		// Handle Changes field, in a stable order
+		uris := make([]string, 0, len(edit.Changes))
+		for uri := range edit.Changes {
+			uris = append(uris, string(uri))
+		}
+		sort.Strings(uris)
+		for _, uri := range uris {
+			if err := tx.applyTextEdits(protocol.DocumentUri(uri), edit.Changes[protocol.DocumentUri(uri)]); err != nil {
+				return fmt.Errorf("failed to apply text edits: %w", err)
+			}
+		}
+
+		// Handle DocumentChanges field
+		for _, change := range edit.DocumentChanges {
+			coreLogger.Warn("Document change: %v", spew.Sdump(change))
+			if err := tx.applyDocumentChange(change); err != nil {
+				return fmt.Errorf("failed to apply document change: %w", err)
 			}
 		}
-		if err := osWriteFile(path, []byte(""), 0644); err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
+		return nil
+	}()
+	if err != nil {
+		if rollbackErr := tx.rollback(); rollbackErr != nil {
+			coreLogger.Error("Failed to roll back workspace edit: %v", rollbackErr)
+			return fmt.Errorf("%w (rolling back also failed, files may be partially modified: %v)", err, rollbackErr)
 		}
+		return fmt.Errorf("%w (no changes were made)", err)
 	}
 
-	if change.DeleteFile != nil {
-		path := strings.TrimPrefix(string(change.DeleteFile.URI), "file://")
-		if change.DeleteFile.Options != nil && change.DeleteFile.Options.Recursive {
-			if err := osRemoveAll(path); err != nil {
-				return fmt.Errorf("failed to delete directory recursively: %w", err)
-			}
-		} else {
-			if err := osRemove(path); err != nil {
-				return fmt.Errorf("failed to delete file: %w", err)
-			}
+	tx.commit()
+
+	if j := GetJournal(); j != nil && len(tx.paths) > 0 {
+		if _, err := j.Record(description, tx.fileChanges()); err != nil {
+			// The edit itself succeeded, it just can't be undone
+			coreLogger.Warn("Failed to record workspace edit in the journal: %v", err)
 		}
 	}
+	return nil
+}
 
-	if change.RenameFile != nil {
-		oldPath := strings.TrimPrefix(string(change.RenameFile.OldURI), "file://")
-		newPath := strings.TrimPrefix(string(change.RenameFile.NewURI), "file://")
-		if change.RenameFile.Options != nil {
-			if !change.RenameFile.Options.Overwrite {
-				if _, err := osStat(newPath); err == nil {
-					return fmt.Errorf("target file already exists and overwrite is not allowed: %s", newPath)
-				}
-			}
+// PreviewWorkspaceEdit simulates the given WorkspaceEdit in memory and returns a unified
+// diff of every file it would change, without writing anything to the filesystem
+func PreviewWorkspaceEdit(edit protocol.WorkspaceEdit) (string, error) {
+	type previewFile struct {
+		// Path the content was originally read from, empty for created files
+		origin   string
+		original []byte
+		content  []byte
+	}
+
+	files := make(map[string]*previewFile)
+	removed := make(map[string]bool)
+	var order []string
+	var notes []string
+
+	load := func(uri protocol.DocumentUri) (*previewFile, error) {
+		path := strings.TrimPrefix(string(uri), "file://")
+		if f, ok := files[path]; ok {
+			return f, nil
 		}
-		if err := osRename(oldPath, newPath); err != nil {
-			return fmt.Errorf("failed to rename file: %w", err)
+		if removed[path] {
+			return nil, fmt.Errorf("file was deleted or renamed by an earlier change: %s", path)
 		}
+		content, err := osReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		f := &previewFile{origin: path, original: content, content: content}
+		files[path] = f
+		order = append(order, path)
+		return f, nil
 	}
 
-	if change.TextDocumentEdit != nil {
-		textEdits := make([]protocol.TextEdit, len(change.TextDocumentEdit.Edits))
-		for i, edit := range change.TextDocumentEdit.Edits {
-			var err error
-			textEdits[i], err = edit.AsTextEdit()
-			if err != nil {
-				return fmt.Errorf("invalid edit type: %w", err)
-			}
+	exists := func(path string) bool {
+		if _, ok := files[path]; ok {
+			return true
 		}
-		return ApplyTextEdits(change.TextDocumentEdit.TextDocument.URI, textEdits)
+		if removed[path] {
+			return false
+		}
+		_, err := osStat(path)
+		return err == nil
 	}
 
-	return nil
-}
+	applyEdits := func(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
+		f, err := load(uri)
+		if err != nil {
+			return err
+		}
+		f.content, err = ApplyTextEditsToContent(f.content, edits)
+		return err
+	}
 
-// ApplyWorkspaceEdit applies the given WorkspaceEdit to the filesystem
-func ApplyWorkspaceEdit(edit protocol.WorkspaceEdit) error {
-	// Handle Changes field
-	for uri, textEdits := range edit.Changes {
-		if err := ApplyTextEdits(uri, textEdits); err != nil {
-			return fmt.Errorf("failed to apply text edits: %w", err)
+	// Handle Changes field, in a stable order
+	uris := make([]string, 0, len(edit.Changes))
+	for uri := range edit.Changes {
+		uris = append(uris, string(uri))
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		if err := applyEdits(protocol.DocumentUri(uri), edit.Changes[protocol.DocumentUri(uri)]); err != nil {
+			return "", fmt.Errorf("failed to apply text edits: %w", err)
 		}
 	}
 
 	// Handle DocumentChanges field
 	for _, change := range edit.DocumentChanges {
-		coreLogger.Warn("Document change: %v", spew.Sdump(change))
-		if err := ApplyDocumentChange(change); err != nil {
-			return fmt.Errorf("failed to apply document change: %w", err)
+		switch {
+		case change.CreateFile != nil:
+			path := strings.TrimPrefix(string(change.CreateFile.URI), "file://")
+			options := change.CreateFile.Options
+			if options != nil && !options.Overwrite && options.IgnoreIfExists && exists(path) {
+				continue
+			}
+			if f, ok := files[path]; ok {
+				f.content = []byte("")
+			} else {
+				files[path] = &previewFile{content: []byte("")}
+				order = append(order, path)
+			}
+			delete(removed, path)
+			notes = append(notes, fmt.Sprintf("Create %s", path))
+
+		case change.DeleteFile != nil:
+			path := strings.TrimPrefix(string(change.DeleteFile.URI), "file://")
+			delete(files, path)
+			removed[path] = true
+			notes = append(notes, fmt.Sprintf("Delete %s", path))
+
+		case change.RenameFile != nil:
+			oldPath := strings.TrimPrefix(string(change.RenameFile.OldURI), "file://")
+			newPath := strings.TrimPrefix(string(change.RenameFile.NewURI), "file://")
+			options := change.RenameFile.Options
+			if options != nil && !options.Overwrite && exists(newPath) {
+				return "", fmt.Errorf("target file already exists and overwrite is not allowed: %s", newPath)
+			}
+			f, err := load(change.RenameFile.OldURI)
+			if err != nil {
+				return "", fmt.Errorf("failed to rename file: %w", err)
+			}
+			delete(files, oldPath)
+			removed[oldPath] = true
+			files[newPath] = f
+			delete(removed, newPath)
+			order = append(order, newPath)
+			notes = append(notes, fmt.Sprintf("Rename %s -> %s", oldPath, newPath))
+
+		case change.TextDocumentEdit != nil:
+			textEdits := make([]protocol.TextEdit, len(change.TextDocumentEdit.Edits))
+			for i, edit := range change.TextDocumentEdit.Edits {
+				var err error
+				textEdits[i], err = edit.AsTextEdit()
+				if err != nil {
+					return "", fmt.Errorf("invalid edit type: %w", err)
+				}
+			}
+			if err := applyEdits(change.TextDocumentEdit.TextDocument.URI, textEdits); err != nil {
+				return "", fmt.Errorf("failed to apply document change: %w", err)
+			}
 		}
 	}
 
-	return nil
+	var output strings.Builder
+	for _, note := range notes {
+		output.WriteString(note + "\n")
+	}
+
+	// Servers don't order document changes consistently, so show the diffs by path
+	sort.Strings(order)
+	seen := make(map[string]bool)
+	for _, path := range order {
+		f, ok := files[path]
+		if !ok || seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		fromFile := f.origin
+		if fromFile == "" {
+			fromFile = "/dev/null"
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitDiffLines(f.original),
+			B:        splitDiffLines(f.content),
+			FromFile: fromFile,
+			ToFile:   path,
+			Context:  3,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to generate diff for %s: %w", path, err)
+		}
+		if diff == "" {
+			continue
+		}
+		if output.Len() > 0 {
+			output.WriteString("\n")
+		}
+		output.WriteString(diff)
+	}
+
+	if output.Len() == 0 {
+		return "No changes", nil
+	}
+	return output.String(), nil
+}
+
+// splitDiffLines splits content into lines that each end with a newline, as expected by difflib
+func splitDiffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
 }
 
 // RangesOverlap checks if two ranges overlap in position
@@ -3,7 +3,9 @@ package utilities
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -18,6 +20,28 @@ type mockFileSystem struct {
 	errors    map[string]error
 }
 
+// isDir reports whether name is a directory holding any of the mocked files
+func (mfs *mockFileSystem) isDir(name string) bool {
+	for k := range mfs.files {
+		if strings.HasPrefix(k, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// siblingPattern matches the temporary and backup paths of siblingPath
+var siblingPattern = regexp.MustCompile(`^\.(.+)\.\d+-\d+\.(tmp|bak)$`)
+
+// writeTarget returns the path a temporary file written by writeFileAtomic is renamed to,
+// so that write errors set up for a path also apply to writing it atomically
+func writeTarget(name string) string {
+	if m := siblingPattern.FindStringSubmatch(filepath.Base(name)); m != nil {
+		return filepath.Join(filepath.Dir(name), m[1])
+	}
+	return name
+}
+
 // Setup mock file system functions
 func setupMockFileSystem(_ *testing.T, mfs *mockFileSystem) func() {
 	// Save original functions
@@ -40,7 +64,7 @@ func setupMockFileSystem(_ *testing.T, mfs *mockFileSystem) func() {
 	}
 
 	osWriteFile = func(filename string, data []byte, perm os.FileMode) error {
-		if err, ok := mfs.errors[filename+"_write"]; ok {
+		if err, ok := mfs.errors[writeTarget(filename)+"_write"]; ok {
 			return err
 		}
 		if mfs.files == nil {
@@ -57,6 +81,12 @@ func setupMockFileSystem(_ *testing.T, mfs *mockFileSystem) func() {
 		if info, ok := mfs.fileStats[name]; ok {
 			return info, nil
 		}
+		if content, ok := mfs.files[name]; ok {
+			return mockFileInfo{name: name, size: int64(len(content)), mode: 0644}, nil
+		}
+		if mfs.isDir(name) {
+			return mockFileInfo{name: name, mode: os.ModeDir | 0755, isDir: true}, nil
+		}
 		return nil, os.ErrNotExist
 	}
 
@@ -93,6 +123,15 @@ func setupMockFileSystem(_ *testing.T, mfs *mockFileSystem) func() {
 			delete(mfs.files, oldpath)
 			return nil
 		}
+		if mfs.isDir(oldpath) {
+			for k, content := range mfs.files {
+				if strings.HasPrefix(k, oldpath+"/") {
+					mfs.files[newpath+strings.TrimPrefix(k, oldpath)] = content
+					delete(mfs.files, k)
+				}
+			}
+			return nil
+		}
 		return os.ErrNotExist
 	}
 
@@ -461,7 +500,7 @@ func TestApplyTextEdit(t *testing.T) {
 	}
 }
 
-func TestApplyTextEdits(t *testing.T) {
+func TestApplyWorkspaceEditTextEdits(t *testing.T) {
 	tests := []struct {
 		name       string
 		uri        protocol.DocumentUri
@@ -644,7 +683,9 @@ func TestApplyTextEdits(t *testing.T) {
 			cleanup := setupMockFileSystem(t, mfs)
 			defer cleanup()
 
-			err := ApplyTextEdits(tt.uri, tt.edits)
+			err := ApplyWorkspaceEdit(protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{tt.uri: tt.edits},
+			}, "Edit")
 			if tt.expectErr {
 				if err == nil {
 					t.Errorf("Expected error but got none")
@@ -656,7 +697,7 @@ func TestApplyTextEdits(t *testing.T) {
 					path := strings.TrimPrefix(string(tt.uri), "file://")
 					if content, ok := mfs.files[path]; ok {
 						if string(content) != tt.expected {
-							t.Errorf("applyTextEdits() result = %q, want %q", string(content), tt.expected)
+							t.Errorf("ApplyWorkspaceEdit() result = %q, want %q", string(content), tt.expected)
 						}
 					} else {
 						t.Errorf("File not found in mock file system")
@@ -667,7 +708,7 @@ func TestApplyTextEdits(t *testing.T) {
 	}
 }
 
-func TestApplyDocumentChange(t *testing.T) {
+func TestApplyWorkspaceEditDocumentChange(t *testing.T) {
 	tests := []struct {
 		name       string
 		change     protocol.DocumentChange
@@ -896,7 +937,9 @@ func TestApplyDocumentChange(t *testing.T) {
 			cleanup := setupMockFileSystem(t, mfs)
 			defer cleanup()
 
-			err := ApplyDocumentChange(tt.change)
+			err := ApplyWorkspaceEdit(protocol.WorkspaceEdit{
+				DocumentChanges: []protocol.DocumentChange{tt.change},
+			}, "Change")
 			if tt.expectErr {
 				if err == nil {
 					t.Errorf("Expected error but got none")
@@ -1060,8 +1103,13 @@ func TestApplyWorkspaceEdit(t *testing.T) {
 				// Missing file causes an error
 			},
 			checkState: func(t *testing.T, mfs *mockFileSystem) {
-				// The first edit might or might not be applied depending on implementation
-				// so we don't check it
+				// The edit to the first file must be rolled back
+				if content := string(mfs.files["/test/file1.txt"]); content != "This is a test line" {
+					t.Errorf("Edit to file1 was not rolled back, content: %s", content)
+				}
+				if len(mfs.files) != 1 {
+					t.Errorf("Unexpected files left behind: %v", mfs.files)
+				}
 			},
 		},
 		{
@@ -1087,8 +1135,123 @@ func TestApplyWorkspaceEdit(t *testing.T) {
 				// Missing file causes an error
 			},
 			checkState: func(t *testing.T, mfs *mockFileSystem) {
-				// The first operation might or might not be applied depending on implementation
-				// so we don't check it
+				// The created file must be removed again
+				if len(mfs.files) != 0 {
+					t.Errorf("Unexpected files left behind: %v", mfs.files)
+				}
+			},
+		},
+		{
+			name: "Delete file",
+			edit: protocol.WorkspaceEdit{
+				DocumentChanges: []protocol.DocumentChange{
+					{
+						DeleteFile: &protocol.DeleteFile{
+							URI: "file:///test/file.txt",
+						},
+					},
+				},
+			},
+			expectErr: false,
+			setupMocks: func(mfs *mockFileSystem) {
+				mfs.files = map[string][]byte{
+					"/test/file.txt": []byte("content"),
+				}
+			},
+			checkState: func(t *testing.T, mfs *mockFileSystem) {
+				// No backup of the deleted file may be left behind
+				if len(mfs.files) != 0 {
+					t.Errorf("Unexpected files left behind: %v", mfs.files)
+				}
+			},
+		},
+		{
+			name: "Rollback of create, rename, delete and edits",
+			edit: protocol.WorkspaceEdit{
+				DocumentChanges: []protocol.DocumentChange{
+					{
+						CreateFile: &protocol.CreateFile{
+							URI: "file:///test/existing.txt",
+							Options: &protocol.CreateFileOptions{
+								Overwrite: true,
+							},
+						},
+					},
+					{
+						RenameFile: &protocol.RenameFile{
+							OldURI: "file:///test/oldname.txt",
+							NewURI: "file:///test/target.txt",
+						},
+					},
+					{
+						DeleteFile: &protocol.DeleteFile{
+							URI: "file:///test/deleted.txt",
+						},
+					},
+					{
+						TextDocumentEdit: &protocol.TextDocumentEdit{
+							TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: protocol.TextDocumentIdentifier{
+									URI: "file:///test/document.txt",
+								},
+							},
+							Edits: []protocol.Or_TextDocumentEdit_edits_Elem{
+								{
+									Value: protocol.TextEdit{
+										Range: protocol.Range{
+											Start: protocol.Position{Line: 0, Character: 5},
+											End:   protocol.Position{Line: 0, Character: 9},
+										},
+										NewText: "was",
+									},
+								},
+							},
+						},
+					},
+					{
+						TextDocumentEdit: &protocol.TextDocumentEdit{
+							TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: protocol.TextDocumentIdentifier{
+									URI: "file:///test/missing.txt", // Missing file causes error
+								},
+							},
+							Edits: []protocol.Or_TextDocumentEdit_edits_Elem{
+								{
+									Value: protocol.TextEdit{NewText: "Modified"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+			setupMocks: func(mfs *mockFileSystem) {
+				mfs.files = map[string][]byte{
+					"/test/existing.txt": []byte("existing content"),
+					"/test/oldname.txt":  []byte("renamed content"),
+					"/test/target.txt":   []byte("overwritten content"),
+					"/test/deleted.txt":  []byte("deleted content"),
+					"/test/document.txt": []byte("This is a test line"),
+				}
+			},
+			checkState: func(t *testing.T, mfs *mockFileSystem) {
+				expected := map[string]string{
+					"/test/existing.txt": "existing content",
+					"/test/oldname.txt":  "renamed content",
+					"/test/target.txt":   "overwritten content",
+					"/test/deleted.txt":  "deleted content",
+					"/test/document.txt": "This is a test line",
+				}
+				for path, content := range expected {
+					if got, ok := mfs.files[path]; !ok {
+						t.Errorf("File %s was not restored", path)
+					} else if string(got) != content {
+						t.Errorf("File %s was not restored, content: %s", path, string(got))
+					}
+				}
+				if len(mfs.files) != len(expected) {
+					t.Errorf("Unexpected files left behind: %v", mfs.files)
+				}
 			},
 		},
 	}
@@ -1100,16 +1263,143 @@ func TestApplyWorkspaceEdit(t *testing.T) {
 			cleanup := setupMockFileSystem(t, mfs)
 			defer cleanup()
 
-			err := ApplyWorkspaceEdit(tt.edit)
+			err := ApplyWorkspaceEdit(tt.edit, tt.name)
 			if tt.expectErr {
 				if err == nil {
 					t.Errorf("Expected error but got none")
 				}
-			} else {
-				if err != nil {
-					t.Errorf("Unexpected error: %v", err)
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			tt.checkState(t, mfs)
+		})
+	}
+}
+
+func TestPreviewWorkspaceEdit(t *testing.T) {
+	tests := []struct {
+		name      string
+		edit      protocol.WorkspaceEdit
+		files     map[string][]byte
+		expected  string
+		expectErr bool
+	}{
+		{
+			name: "Changes in multiple files",
+			edit: protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					"file:///test/b.txt": {
+						{
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 1, Character: 0},
+								End:   protocol.Position{Line: 1, Character: 3},
+							},
+							NewText: "bar",
+						},
+					},
+					"file:///test/a.txt": {
+						{
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 0, Character: 0},
+								End:   protocol.Position{Line: 0, Character: 3},
+							},
+							NewText: "bar",
+						},
+					},
+				},
+			},
+			files: map[string][]byte{
+				"/test/a.txt": []byte("foo\nline 2\n"),
+				"/test/b.txt": []byte("line 1\nfoo\n"),
+			},
+			expected: "--- /test/a.txt\n+++ /test/a.txt\n@@ -1,2 +1,2 @@\n-foo\n+bar\n line 2\n" +
+				"\n--- /test/b.txt\n+++ /test/b.txt\n@@ -1,2 +1,2 @@\n line 1\n-foo\n+bar\n",
+		},
+		{
+			name: "Create, rename and delete",
+			edit: protocol.WorkspaceEdit{
+				DocumentChanges: []protocol.DocumentChange{
+					{
+						CreateFile: &protocol.CreateFile{URI: "file:///test/new.txt"},
+					},
+					{
+						TextDocumentEdit: &protocol.TextDocumentEdit{
+							TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: "file:///test/new.txt"},
+							},
+							Edits: []protocol.Or_TextDocumentEdit_edits_Elem{
+								{Value: protocol.TextEdit{NewText: "hello\n"}},
+							},
+						},
+					},
+					{
+						RenameFile: &protocol.RenameFile{
+							OldURI: "file:///test/old.txt",
+							NewURI: "file:///test/renamed.txt",
+						},
+					},
+					{
+						DeleteFile: &protocol.DeleteFile{URI: "file:///test/gone.txt"},
+					},
+				},
+			},
+			files: map[string][]byte{
+				"/test/old.txt":  []byte("content\n"),
+				"/test/gone.txt": []byte("content\n"),
+			},
+			expected: "Create /test/new.txt\nRename /test/old.txt -> /test/renamed.txt\nDelete /test/gone.txt\n" +
+				"\n--- /dev/null\n+++ /test/new.txt\n@@ -0,0 +1 @@\n+hello\n",
+		},
+		{
+			name:     "No changes",
+			edit:     protocol.WorkspaceEdit{},
+			expected: "No changes",
+		},
+		{
+			name: "Missing file",
+			edit: protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					"file:///test/missing.txt": {{NewText: "x"}},
+				},
+			},
+			files:     map[string][]byte{},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mfs := &mockFileSystem{files: tt.files}
+			cleanup := setupMockFileSystem(t, mfs)
+			defer cleanup()
+
+			before := make(map[string]string)
+			for path, content := range tt.files {
+				before[path] = string(content)
+			}
+
+			result, err := PreviewWorkspaceEdit(tt.edit)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, result)
+			}
+
+			// Previewing must not touch the filesystem
+			if len(mfs.files) != len(before) {
+				t.Errorf("Files were created or deleted: %v", mfs.files)
+			}
+			for path, content := range before {
+				if string(mfs.files[path]) != content {
+					t.Errorf("File %s was modified", path)
 				}
-				tt.checkState(t, mfs)
 			}
 		})
 	}
@@ -0,0 +1,302 @@
+package utilities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileChange records the state of one file before and after a change set
+type FileChange struct {
+	Path string `json:"path"`
+	// IsDir is set when a directory was deleted or renamed, which can't be undone
+	IsDir bool `json:"isDir,omitempty"`
+	// Existed reports whether the file existed before the change, with Before and Mode
+	// holding its content and permissions
+	Existed bool        `json:"existed"`
+	Before  []byte      `json:"before,omitempty"`
+	Mode    os.FileMode `json:"mode,omitempty"`
+	// AfterHash is the SHA-256 of the content after the change, empty if the change
+	// deleted the file
+	AfterHash string `json:"afterHash,omitempty"`
+}
+
+// ChangeSet is a set of file modifications applied together by one workspace edit
+type ChangeSet struct {
+	ID          int          `json:"id"`
+	Time        time.Time    `json:"time"`
+	Description string       `json:"description"`
+	Files       []FileChange `json:"files"`
+	Undone      bool         `json:"undone,omitempty"`
+
+	// undoing is set while the change set is being undone, so that it is undone once
+	undoing bool
+}
+
+// Journal records the change sets applied through ApplyWorkspaceEdit in memory and on
+// disk, one JSON file per change set, so that they can be listed and undone
+type Journal struct {
+	mu         sync.Mutex
+	dir        string
+	maxEntries int
+	changes    []*ChangeSet
+	nextID     int
+}
+
+var (
+	journalMu sync.RWMutex
+	journal   *Journal
+)
+
+// SetJournal sets the journal that workspace edits are recorded in, nil disables recording
+func SetJournal(j *Journal) {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+	journal = j
+}
+
+// GetJournal returns the journal that workspace edits are recorded in, or nil if disabled
+func GetJournal() *Journal {
+	journalMu.RLock()
+	defer journalMu.RUnlock()
+	return journal
+}
+
+// DefaultJournalDir returns the directory in the user cache directory used for the
+// journal of a workspace
+func DefaultJournalDir(workspaceDir string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(workspaceDir))
+	return filepath.Join(cacheDir, "mcp-language-server", "journal", hex.EncodeToString(sum[:8])), nil
+}
+
+// OpenJournal opens the journal stored in dir, creating the directory if needed and
+// loading the change sets recorded by earlier runs. At most maxEntries change sets are
+// kept, older ones are removed as new ones are recorded.
+func OpenJournal(dir string, maxEntries int) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	j := &Journal{dir: dir, maxEntries: maxEntries, nextID: 1}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			coreLogger.Warn("Failed to read journal entry %s: %v", entry.Name(), err)
+			continue
+		}
+		var cs ChangeSet
+		if err := json.Unmarshal(data, &cs); err != nil {
+			coreLogger.Warn("Failed to parse journal entry %s: %v", entry.Name(), err)
+			continue
+		}
+		j.changes = append(j.changes, &cs)
+		if cs.ID >= j.nextID {
+			j.nextID = cs.ID + 1
+		}
+	}
+
+	sort.Slice(j.changes, func(a, b int) bool {
+		return j.changes[a].ID < j.changes[b].ID
+	})
+	j.prune()
+
+	return j, nil
+}
+
+func (j *Journal) entryPath(id int) string {
+	return filepath.Join(j.dir, strconv.Itoa(id)+".json")
+}
+
+func (j *Journal) save(cs *ChangeSet) error {
+	data, err := json.Marshal(cs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.entryPath(cs.ID), data, 0600)
+}
+
+// prune removes the oldest change sets beyond maxEntries
+func (j *Journal) prune() {
+	if j.maxEntries <= 0 || len(j.changes) <= j.maxEntries {
+		return
+	}
+	for _, cs := range j.changes[:len(j.changes)-j.maxEntries] {
+		if err := os.Remove(j.entryPath(cs.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			coreLogger.Warn("Failed to remove journal entry %d: %v", cs.ID, err)
+		}
+	}
+	j.changes = append([]*ChangeSet(nil), j.changes[len(j.changes)-j.maxEntries:]...)
+}
+
+// Record adds a change set to the journal and returns it
+func (j *Journal) Record(description string, files []FileChange) (*ChangeSet, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	cs := &ChangeSet{
+		ID:          j.nextID,
+		Time:        time.Now(),
+		Description: description,
+		Files:       files,
+	}
+	j.nextID++
+
+	if err := j.save(cs); err != nil {
+		return nil, fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	j.changes = append(j.changes, cs)
+	j.prune()
+
+	return cs, nil
+}
+
+// Changes returns the recorded change sets, most recent first
+func (j *Journal) Changes() []ChangeSet {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	result := make([]ChangeSet, 0, len(j.changes))
+	for i := len(j.changes) - 1; i >= 0; i-- {
+		result = append(result, *j.changes[i])
+	}
+	return result
+}
+
+// Conflicts returns a description of every file in a change set whose content on disk
+// is no longer what the change set left behind
+func (cs *ChangeSet) Conflicts() []string {
+	var conflicts []string
+	for _, file := range cs.Files {
+		if file.IsDir {
+			continue
+		}
+		content, err := osReadFile(file.Path)
+		switch {
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v", file.Path, err))
+		case err != nil && file.AfterHash != "":
+			conflicts = append(conflicts, fmt.Sprintf("%s: deleted since the change", file.Path))
+		case err == nil && file.AfterHash == "":
+			conflicts = append(conflicts, fmt.Sprintf("%s: recreated since the change", file.Path))
+		case err == nil && contentHash(content) != file.AfterHash:
+			conflicts = append(conflicts, fmt.Sprintf("%s: modified since the change", file.Path))
+		}
+	}
+	return conflicts
+}
+
+// Undo restores every file in a change set to its state before the change, as a single
+// workspace edit that is itself recorded. Unless force is set, it fails without changing
+// anything if any of the files were modified since.
+func (j *Journal) Undo(id int, force bool) (*ChangeSet, error) {
+	cs, err := j.startUndo(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := cs.restore(force)
+
+	j.mu.Lock()
+	cs.undoing = false
+	if err == nil {
+		cs.Undone = true
+		if err := j.save(cs); err != nil {
+			coreLogger.Warn("Failed to update journal entry %d: %v", cs.ID, err)
+		}
+	}
+	j.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	undo, err := j.Record(fmt.Sprintf("Undo change %d: %s", cs.ID, cs.Description), tx.fileChanges())
+	if err != nil {
+		coreLogger.Warn("Failed to record undo of change %d: %v", cs.ID, err)
+	}
+	return undo, nil
+}
+
+// startUndo finds a change set and marks it as being undone, unless it was undone
+// already or is being undone by another call
+func (j *Journal) startUndo(id int) (*ChangeSet, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, cs := range j.changes {
+		if cs.ID != id {
+			continue
+		}
+		if cs.Undone {
+			return nil, fmt.Errorf("change %d was already undone", id)
+		}
+		if cs.undoing {
+			return nil, fmt.Errorf("change %d is already being undone", id)
+		}
+		cs.undoing = true
+		return cs, nil
+	}
+	return nil, fmt.Errorf("change %d not found", id)
+}
+
+// restore writes back the files of a change set as they were before the change, and
+// returns the committed transaction that did it
+func (cs *ChangeSet) restore(force bool) (*editTransaction, error) {
+	for _, file := range cs.Files {
+		if file.IsDir {
+			return nil, fmt.Errorf("change %d can't be undone because it deleted or renamed the directory %s", cs.ID, file.Path)
+		}
+	}
+	if conflicts := cs.Conflicts(); len(conflicts) > 0 && !force {
+		return nil, fmt.Errorf("change %d conflicts with later modifications, undo with force to overwrite them:\n%s", cs.ID, strings.Join(conflicts, "\n"))
+	}
+
+	var tx editTransaction
+	err := func() error {
+		for _, file := range cs.Files {
+			if file.Existed {
+				if err := tx.writeFileMode(file.Path, file.Before, file.Mode); err != nil {
+					return fmt.Errorf("failed to restore %s: %w", file.Path, err)
+				}
+			} else if exists(file.Path) {
+				if err := tx.moveAside(file.Path); err != nil {
+					return fmt.Errorf("failed to remove %s: %w", file.Path, err)
+				}
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		if rollbackErr := tx.rollback(); rollbackErr != nil {
+			return nil, fmt.Errorf("%w (rolling back also failed, files may be partially modified: %v)", err, rollbackErr)
+		}
+		return nil, fmt.Errorf("%w (no changes were made)", err)
+	}
+	tx.commit()
+	return &tx, nil
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,225 @@
+package utilities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+func journalTestEdit() protocol.WorkspaceEdit {
+	return protocol.WorkspaceEdit{
+		Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+			"file:///test/file.txt": {
+				{
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 5},
+						End:   protocol.Position{Line: 0, Character: 9},
+					},
+					NewText: "was",
+				},
+			},
+		},
+		DocumentChanges: []protocol.DocumentChange{
+			{
+				CreateFile: &protocol.CreateFile{
+					URI: "file:///test/newfile.txt",
+				},
+			},
+			{
+				DeleteFile: &protocol.DeleteFile{
+					URI: "file:///test/deleted.txt",
+				},
+			},
+		},
+	}
+}
+
+func setupJournal(t *testing.T) *Journal {
+	j, err := OpenJournal(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	SetJournal(j)
+	t.Cleanup(func() { SetJournal(nil) })
+	return j
+}
+
+func TestJournalUndo(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*mockFileSystem)
+		force       bool
+		expectErr   string
+		expectFiles map[string]string
+	}{
+		{
+			name: "Undo restores all files",
+			expectFiles: map[string]string{
+				"/test/file.txt":    "This is a test line",
+				"/test/deleted.txt": "deleted content",
+			},
+		},
+		{
+			name: "Conflict when a file was modified since",
+			modify: func(mfs *mockFileSystem) {
+				mfs.files["/test/file.txt"] = []byte("Modified by someone else")
+			},
+			expectErr: "/test/file.txt: modified since the change",
+			expectFiles: map[string]string{
+				"/test/file.txt":    "Modified by someone else",
+				"/test/newfile.txt": "",
+			},
+		},
+		{
+			name: "Conflict when a deleted file was recreated",
+			modify: func(mfs *mockFileSystem) {
+				mfs.files["/test/deleted.txt"] = []byte("recreated")
+			},
+			expectErr: "/test/deleted.txt: recreated since the change",
+			expectFiles: map[string]string{
+				"/test/file.txt":    "This was test line",
+				"/test/newfile.txt": "",
+				"/test/deleted.txt": "recreated",
+			},
+		},
+		{
+			name: "Force overwrites later modifications",
+			modify: func(mfs *mockFileSystem) {
+				mfs.files["/test/file.txt"] = []byte("Modified by someone else")
+			},
+			force: true,
+			expectFiles: map[string]string{
+				"/test/file.txt":    "This is a test line",
+				"/test/deleted.txt": "deleted content",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mfs := &mockFileSystem{
+				files: map[string][]byte{
+					"/test/file.txt":    []byte("This is a test line"),
+					"/test/deleted.txt": []byte("deleted content"),
+				},
+			}
+			cleanup := setupMockFileSystem(t, mfs)
+			defer cleanup()
+			j := setupJournal(t)
+
+			if err := ApplyWorkspaceEdit(journalTestEdit(), "Test edit"); err != nil {
+				t.Fatalf("Failed to apply edit: %v", err)
+			}
+
+			changes := j.Changes()
+			if len(changes) != 1 {
+				t.Fatalf("Expected 1 recorded change, got %d", len(changes))
+			}
+			if changes[0].Description != "Test edit" || len(changes[0].Files) != 3 {
+				t.Errorf("Unexpected change recorded: %+v", changes[0])
+			}
+
+			if tt.modify != nil {
+				tt.modify(mfs)
+			}
+
+			undo, err := j.Undo(changes[0].ID, tt.force)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			} else {
+				if undo == nil || undo.ID != 2 {
+					t.Errorf("Expected the undo to be recorded as change 2, got %+v", undo)
+				}
+				if _, err := j.Undo(changes[0].ID, false); err == nil {
+					t.Errorf("Expected an error when undoing a change twice")
+				}
+			}
+
+			if len(mfs.files) != len(tt.expectFiles) {
+				t.Errorf("Unexpected files: %v", mfs.files)
+			}
+			for path, content := range tt.expectFiles {
+				if got, ok := mfs.files[path]; !ok {
+					t.Errorf("File %s not found", path)
+				} else if string(got) != content {
+					t.Errorf("File %s has content %q, want %q", path, string(got), content)
+				}
+			}
+		})
+	}
+}
+
+func TestJournalUndoInProgress(t *testing.T) {
+	mfs := &mockFileSystem{
+		files: map[string][]byte{
+			"/test/file.txt":    []byte("This is a test line"),
+			"/test/deleted.txt": []byte("deleted content"),
+		},
+	}
+	cleanup := setupMockFileSystem(t, mfs)
+	defer cleanup()
+	j := setupJournal(t)
+
+	if err := ApplyWorkspaceEdit(journalTestEdit(), "Test edit"); err != nil {
+		t.Fatalf("Failed to apply edit: %v", err)
+	}
+	id := j.Changes()[0].ID
+
+	// A change set that is being undone can't be undone by another call
+	cs, err := j.startUndo(id)
+	if err != nil {
+		t.Fatalf("Failed to start undo: %v", err)
+	}
+	if _, err := j.Undo(id, false); err == nil || !strings.Contains(err.Error(), "already being undone") {
+		t.Errorf("Expected an error while the change is being undone, got %v", err)
+	}
+	j.mu.Lock()
+	cs.undoing = false
+	j.mu.Unlock()
+
+	// A failed undo can be retried
+	mfs.files["/test/file.txt"] = []byte("Modified by someone else")
+	if _, err := j.Undo(id, false); err == nil {
+		t.Fatalf("Expected a conflict")
+	}
+	if _, err := j.Undo(id, true); err != nil {
+		t.Errorf("Expected the undo to be retried after a conflict, got %v", err)
+	}
+}
+
+func TestJournalPersistence(t *testing.T) {
+	dir := t.TempDir()
+	j, err := OpenJournal(dir, 2)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+
+	for _, description := range []string{"first", "second", "third"} {
+		if _, err := j.Record(description, []FileChange{{Path: "/test/file.txt", Existed: true}}); err != nil {
+			t.Fatalf("Failed to record change: %v", err)
+		}
+	}
+
+	reopened, err := OpenJournal(dir, 2)
+	if err != nil {
+		t.Fatalf("Failed to reopen journal: %v", err)
+	}
+
+	changes := reopened.Changes()
+	if len(changes) != 2 || changes[0].Description != "third" || changes[1].Description != "second" {
+		t.Fatalf("Expected the two most recent changes, got %+v", changes)
+	}
+
+	cs, err := reopened.Record("fourth", nil)
+	if err != nil {
+		t.Fatalf("Failed to record change: %v", err)
+	}
+	if cs.ID != 4 {
+		t.Errorf("Expected IDs to continue after reopening, got %d", cs.ID)
+	}
+}
@@ -0,0 +1,274 @@
+package utilities
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// tempCounter keeps temporary and backup file names unique within the process
+var tempCounter atomic.Uint64
+
+// editTransaction applies the operations of a workspace edit one at a time, recording how
+// to undo each of them so that a failed edit can be rolled back to the original state
+type editTransaction struct {
+	// undo holds the inverse of every completed operation, in the order they were applied
+	undo []func() error
+	// backups holds files and directories moved aside by deletes and overwrites, which
+	// are only removed once the whole edit has succeeded
+	backups []string
+	// before holds the original state of every path touched, in the order touched
+	before map[string]FileChange
+	paths  []string
+}
+
+// siblingPath returns an unused hidden path in the same directory as path, so that
+// renames between them stay on the same filesystem and are atomic
+func siblingPath(path, suffix string) string {
+	return filepath.Join(filepath.Dir(path),
+		fmt.Sprintf(".%s.%d-%d.%s", filepath.Base(path), os.Getpid(), tempCounter.Add(1), suffix))
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// so that path never holds partially written content
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := siblingPath(path, "tmp")
+	if err := osWriteFile(tmp, data, perm); err != nil {
+		_ = osRemove(tmp)
+		return err
+	}
+	if err := osRename(tmp, path); err != nil {
+		_ = osRemove(tmp)
+		return err
+	}
+	return nil
+}
+
+// fileMode returns the permissions of an existing file, or the default for new files
+func fileMode(path string) os.FileMode {
+	if info, err := osStat(path); err == nil && info.Mode().Perm() != 0 {
+		return info.Mode().Perm()
+	}
+	return 0644
+}
+
+func exists(path string) bool {
+	_, err := osStat(path)
+	return err == nil
+}
+
+// capture records the state of path before the transaction first touches it
+func (t *editTransaction) capture(path string) error {
+	if _, ok := t.before[path]; ok {
+		return nil
+	}
+	if t.before == nil {
+		t.before = make(map[string]FileChange)
+	}
+
+	change := FileChange{Path: path}
+	info, err := osStat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to stat file: %w", err)
+	case info.IsDir():
+		change.IsDir = true
+		change.Existed = true
+	default:
+		content, err := osReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		change.Existed = true
+		change.Before = content
+		change.Mode = info.Mode().Perm()
+	}
+
+	t.before[path] = change
+	t.paths = append(t.paths, path)
+	return nil
+}
+
+// fileChanges returns the original and current state of every path touched, sorted by
+// path, for the journal
+func (t *editTransaction) fileChanges() []FileChange {
+	paths := append([]string(nil), t.paths...)
+	sort.Strings(paths)
+
+	changes := make([]FileChange, 0, len(paths))
+	for _, path := range paths {
+		change := t.before[path]
+		if !change.IsDir {
+			if content, err := osReadFile(path); err == nil {
+				change.AfterHash = contentHash(content)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// writeFile replaces the content of path, creating it if needed
+func (t *editTransaction) writeFile(path string, content []byte) error {
+	return t.writeFileMode(path, content, 0)
+}
+
+// writeFileMode replaces the content of path, creating it with the given permissions if
+// needed. If mode is zero, existing permissions are kept.
+func (t *editTransaction) writeFileMode(path string, content []byte, mode os.FileMode) error {
+	if err := t.capture(path); err != nil {
+		return err
+	}
+	if mode == 0 {
+		mode = fileMode(path)
+	}
+	original, err := osReadFile(path)
+	existed := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if err := writeFileAtomic(path, content, mode); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if existed {
+		t.undo = append(t.undo, func() error { return writeFileAtomic(path, original, mode) })
+	} else {
+		t.undo = append(t.undo, func() error { return osRemove(path) })
+	}
+	return nil
+}
+
+// moveAside moves path to a backup location that is removed on commit and moved back on
+// rollback
+func (t *editTransaction) moveAside(path string) error {
+	if err := t.capture(path); err != nil {
+		return err
+	}
+	backup := siblingPath(path, "bak")
+	if err := osRename(path, backup); err != nil {
+		return err
+	}
+	t.backups = append(t.backups, backup)
+	t.undo = append(t.undo, func() error { return osRename(backup, path) })
+	return nil
+}
+
+func (t *editTransaction) applyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
+	path := strings.TrimPrefix(string(uri), "file://")
+
+	content, err := osReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	newContent, err := ApplyTextEditsToContent(content, edits)
+	if err != nil {
+		return err
+	}
+
+	return t.writeFile(path, newContent)
+}
+
+func (t *editTransaction) applyDocumentChange(change protocol.DocumentChange) error {
+	if change.CreateFile != nil {
+		path := strings.TrimPrefix(string(change.CreateFile.URI), "file://")
+		options := change.CreateFile.Options
+		if options != nil && !options.Overwrite && options.IgnoreIfExists && exists(path) {
+			return nil // File exists and we're ignoring it
+		}
+		if err := t.writeFile(path, []byte("")); err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+	}
+
+	if change.DeleteFile != nil {
+		path := strings.TrimPrefix(string(change.DeleteFile.URI), "file://")
+		options := change.DeleteFile.Options
+		info, err := osStat(path)
+		if err != nil {
+			if options != nil && options.IgnoreIfNotExists && errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+		if info.IsDir() && (options == nil || !options.Recursive) {
+			return fmt.Errorf("failed to delete file: %s is a directory and recursive is not set", path)
+		}
+		if err := t.moveAside(path); err != nil {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+
+	if change.RenameFile != nil {
+		oldPath := strings.TrimPrefix(string(change.RenameFile.OldURI), "file://")
+		newPath := strings.TrimPrefix(string(change.RenameFile.NewURI), "file://")
+		if err := t.capture(oldPath); err != nil {
+			return fmt.Errorf("failed to rename file: %w", err)
+		}
+		if err := t.capture(newPath); err != nil {
+			return fmt.Errorf("failed to rename file: %w", err)
+		}
+		if exists(newPath) {
+			if change.RenameFile.Options != nil && !change.RenameFile.Options.Overwrite {
+				return fmt.Errorf("target file already exists and overwrite is not allowed: %s", newPath)
+			}
+			if err := t.moveAside(newPath); err != nil {
+				return fmt.Errorf("failed to rename file: %w", err)
+			}
+		}
+		if err := osRename(oldPath, newPath); err != nil {
+			return fmt.Errorf("failed to rename file: %w", err)
+		}
+		t.undo = append(t.undo, func() error { return osRename(newPath, oldPath) })
+	}
+
+	if change.TextDocumentEdit != nil {
+		textEdits := make([]protocol.TextEdit, len(change.TextDocumentEdit.Edits))
+		for i, edit := range change.TextDocumentEdit.Edits {
+			var err error
+			textEdits[i], err = edit.AsTextEdit()
+			if err != nil {
+				return fmt.Errorf("invalid edit type: %w", err)
+			}
+		}
+		return t.applyTextEdits(change.TextDocumentEdit.TextDocument.URI, textEdits)
+	}
+
+	return nil
+}
+
+// rollback undoes every completed operation in reverse order
+func (t *editTransaction) rollback() error {
+	var errs []error
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		if err := t.undo[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.undo = nil
+	t.backups = nil
+	t.before = nil
+	t.paths = nil
+	return errors.Join(errs...)
+}
+
+// commit removes the backups of deleted and overwritten files. Failing to remove a
+// backup doesn't affect the edit itself, so it is only logged.
+func (t *editTransaction) commit() {
+	for _, backup := range t.backups {
+		if err := osRemoveAll(backup); err != nil {
+			coreLogger.Warn("Failed to remove backup %s: %v", backup, err)
+		}
+	}
+	t.undo = nil
+	t.backups = nil
+}
@@ -27,9 +27,10 @@ type WorkspaceWatcher struct {
 	debounceMap map[string]*time.Timer
 	debounceMu  sync.Mutex
 
-	// File watchers registered by the server
-	registrations  []protocol.FileSystemWatcher
-	registrationMu sync.RWMutex
+	// File watchers registered by the server, all of them and by registration ID
+	registrations     []protocol.FileSystemWatcher
+	registrationsByID map[string][]protocol.FileSystemWatcher
+	registrationMu    sync.RWMutex
 
 	// Gitignore matcher
 	gitignore *GitignoreMatcher
@@ -43,20 +44,23 @@ func NewWorkspaceWatcher(client LSPClient) *WorkspaceWatcher {
 // NewWorkspaceWatcherWithConfig creates a new workspace watcher with custom configuration
 func NewWorkspaceWatcherWithConfig(client LSPClient, config *WatcherConfig) *WorkspaceWatcher {
 	return &WorkspaceWatcher{
-		client:        client,
-		config:        config,
-		debounceMap:   make(map[string]*time.Timer),
-		registrations: []protocol.FileSystemWatcher{},
+		client:            client,
+		config:            config,
+		debounceMap:       make(map[string]*time.Timer),
+		registrations:     []protocol.FileSystemWatcher{},
+		registrationsByID: make(map[string][]protocol.FileSystemWatcher),
 	}
 }
 
-// AddRegistrations adds file watchers to track
+// AddRegistrations adds file watchers to track. Watchers registered again with the same
+// ID, as after the language server restarts, replace the previous ones.
 func (w *WorkspaceWatcher) AddRegistrations(ctx context.Context, id string, watchers []protocol.FileSystemWatcher) {
 	w.registrationMu.Lock()
 	defer w.registrationMu.Unlock()
 
 	// Add new watchers
-	w.registrations = append(w.registrations, watchers...)
+	w.registrationsByID[id] = watchers
+	w.rebuildRegistrations()
 
 	// Log registration information
 	watcherLogger.Info("Added %d file watcher registrations (id: %s), total: %d",
@@ -153,6 +157,27 @@ func (w *WorkspaceWatcher) AddRegistrations(ctx context.Context, id string, watc
 	}()
 }
 
+// RemoveRegistrations stops tracking the file watchers registered with an ID, when the
+// language server unregisters them or a restarted server replaces them
+func (w *WorkspaceWatcher) RemoveRegistrations(id string) {
+	w.registrationMu.Lock()
+	defer w.registrationMu.Unlock()
+
+	delete(w.registrationsByID, id)
+	w.rebuildRegistrations()
+
+	watcherLogger.Info("Removed file watcher registrations (id: %s), total: %d", id, len(w.registrations))
+}
+
+// rebuildRegistrations collects the watchers of all registrations. The caller must hold
+// registrationMu.
+func (w *WorkspaceWatcher) rebuildRegistrations() {
+	w.registrations = w.registrations[:0]
+	for _, registered := range w.registrationsByID {
+		w.registrations = append(w.registrations, registered...)
+	}
+}
+
 // WatchWorkspace sets up file watching for a workspace
 func (w *WorkspaceWatcher) WatchWorkspace(ctx context.Context, workspacePath string) {
 	w.workspacePath = workspacePath
@@ -170,6 +195,7 @@ func (w *WorkspaceWatcher) WatchWorkspace(ctx context.Context, workspacePath str
 	lsp.RegisterFileWatchHandler(func(id string, watchers []protocol.FileSystemWatcher) {
 		w.AddRegistrations(ctx, id, watchers)
 	})
+	lsp.RegisterFileUnwatchHandler(w.RemoveRegistrations)
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/isaacphi/mcp-language-server/internal/logging"
 	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
 	"github.com/isaacphi/mcp-language-server/internal/watcher"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -20,10 +21,17 @@ import (
 // Create a logger for the core component
 var coreLogger = logging.NewLogger(logging.Core)
 
+// maxJournalEntries is the number of change sets kept in the undo journal
+const maxJournalEntries = 100
+
 type config struct {
 	workspaceDir string
 	lspCommand   string
 	lspArgs      []string
+	journalDir   string
+
+	diagnosticsTimeout time.Duration
+	requestTimeout     time.Duration
 }
 
 type mcpServer struct {
@@ -39,6 +47,9 @@ func parseConfig() (*config, error) {
 	cfg := &config{}
 	flag.StringVar(&cfg.workspaceDir, "workspace", "", "Path to workspace directory")
 	flag.StringVar(&cfg.lspCommand, "lsp", "", "LSP command to run (args should be passed after --)")
+	flag.StringVar(&cfg.journalDir, "journal", "", "Directory for the undo journal of file changes (defaults to a directory in the user cache directory)")
+	flag.DurationVar(&cfg.diagnosticsTimeout, "diagnostics-timeout", lsp.DefaultDiagnosticsTimeout, "How long to wait for the language server to check a file before reporting its diagnostics")
+	flag.DurationVar(&cfg.requestTimeout, "request-timeout", lsp.DefaultRequestTimeout, "How long to wait for the language server to respond to a request before cancelling it")
 	flag.Parse()
 
 	// Get remaining args after -- as LSP arguments
@@ -59,6 +70,14 @@ func parseConfig() (*config, error) {
 		return nil, fmt.Errorf("workspace directory does not exist: %s", cfg.workspaceDir)
 	}
 
+	if cfg.diagnosticsTimeout <= 0 {
+		return nil, fmt.Errorf("diagnostics timeout must be positive")
+	}
+
+	if cfg.requestTimeout <= 0 {
+		return nil, fmt.Errorf("request timeout must be positive")
+	}
+
 	// Validate LSP command
 	if cfg.lspCommand == "" {
 		return nil, fmt.Errorf("LSP command is required")
@@ -89,6 +108,8 @@ func (s *mcpServer) initializeLSP() error {
 	if err != nil {
 		return fmt.Errorf("failed to create LSP client: %v", err)
 	}
+	client.SetDiagnosticsTimeout(s.config.diagnosticsTimeout)
+	client.SetRequestTimeout(s.config.requestTimeout)
 	s.lspClient = client
 	s.workspaceWatcher = watcher.NewWorkspaceWatcher(client)
 
@@ -103,11 +124,35 @@ func (s *mcpServer) initializeLSP() error {
 	return client.WaitForServerReady(s.ctx)
 }
 
+// openJournal enables the undo journal. Changes can still be made without it, so
+// failures are only logged.
+func (s *mcpServer) openJournal() {
+	dir := s.config.journalDir
+	if dir == "" {
+		var err error
+		dir, err = utilities.DefaultJournalDir(s.config.workspaceDir)
+		if err != nil {
+			coreLogger.Warn("Undo journal disabled, no journal directory: %v", err)
+			return
+		}
+	}
+
+	journal, err := utilities.OpenJournal(dir, maxJournalEntries)
+	if err != nil {
+		coreLogger.Warn("Undo journal disabled: %v", err)
+		return
+	}
+	utilities.SetJournal(journal)
+	coreLogger.Info("Recording file changes in %s", dir)
+}
+
 func (s *mcpServer) start() error {
 	if err := s.initializeLSP(); err != nil {
 		return err
 	}
 
+	s.openJournal()
+
 	s.mcpServer = server.NewMCPServer(
 		"MCP Language Server",
 		"v0.0.2",
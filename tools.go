@@ -12,7 +12,7 @@ func (s *mcpServer) registerTools() error {
 	coreLogger.Debug("Registering MCP tools")
 
 	applyTextEditTool := mcp.NewTool("edit_file",
-		mcp.WithDescription("Apply multiple text edits to a file."),
+		mcp.WithDescription("Apply multiple text edits to a file. Each edit replaces either a range of lines, given by startLine and endLine, or a snippet of text, given by oldText, which must occur exactly once in the file. Prefer oldText when earlier edits may have shifted the line numbers. To avoid editing lines that changed since you read the file, pass the current text of the lines in expectedText, or the file hash returned by the definition tool or a previous edit in expectedFileHash. Mismatching edits are rejected and the current content of their lines is shown."),
 		mcp.WithArray("edits",
 			mcp.Required(),
 			mcp.Description("List of edits to apply"),
@@ -31,14 +31,29 @@ func (s *mcpServer) registerTools() error {
 						"type":        "string",
 						"description": "Replacement text. Replace with the new text. Leave blank to remove lines.",
 					},
+					"oldText": map[string]any{
+						"type":        "string",
+						"description": "Text to replace instead of a range of lines. It must occur exactly once in the file, include surrounding lines to make it unique.",
+					},
+					"expectedText": map[string]any{
+						"type":        "string",
+						"description": "The current text of lines startLine to endLine. If the file doesn't match, the edit is rejected.",
+					},
+					"expectedFileHash": map[string]any{
+						"type":        "string",
+						"description": "The SHA-256 of the whole file, as returned by the definition tool or a previous edit_file call. If the file doesn't match, the edit is rejected.",
+					},
 				},
-				"required": []string{"startLine", "endLine"},
 			}),
 		),
 		mcp.WithString("filePath",
 			mcp.Required(),
 			mcp.Description("Path to the file to edit"),
 		),
+		mcp.WithBoolean("showDiagnostics",
+			mcp.Description("Wait for the language server to check the edited file and list the diagnostics the edits introduced or resolved"),
+			mcp.DefaultBool(false),
+		),
 	)
 
 	s.mcpServer.AddTool(applyTextEditTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -67,27 +82,60 @@ func (s *mcpServer) registerTools() error {
 				return mcp.NewToolResultError("each edit must be an object"), nil
 			}
 
-			startLine, ok := editMap["startLine"].(float64)
-			if !ok {
-				return mcp.NewToolResultError("startLine must be a number"), nil
+			newText, _ := editMap["newText"].(string) // newText can be empty
+
+			// Edits are anchored either on oldText or on line numbers
+			var oldText *string
+			var startLine, endLine float64
+			if oldTextArg, ok := editMap["oldText"]; ok {
+				text, ok := oldTextArg.(string)
+				if !ok {
+					return mcp.NewToolResultError("oldText must be a string"), nil
+				}
+				oldText = &text
+			} else {
+				startLine, ok = editMap["startLine"].(float64)
+				if !ok {
+					return mcp.NewToolResultError("startLine must be a number, or oldText must be given"), nil
+				}
+
+				endLine, ok = editMap["endLine"].(float64)
+				if !ok {
+					return mcp.NewToolResultError("endLine must be a number, or oldText must be given"), nil
+				}
 			}
 
-			endLine, ok := editMap["endLine"].(float64)
-			if !ok {
-				return mcp.NewToolResultError("endLine must be a number"), nil
+			var expectedText *string
+			if expectedArg, ok := editMap["expectedText"]; ok {
+				text, ok := expectedArg.(string)
+				if !ok {
+					return mcp.NewToolResultError("expectedText must be a string"), nil
+				}
+				expectedText = &text
 			}
 
-			newText, _ := editMap["newText"].(string) // newText can be empty
+			expectedFileHash, ok := editMap["expectedFileHash"].(string)
+			if _, present := editMap["expectedFileHash"]; present && !ok {
+				return mcp.NewToolResultError("expectedFileHash must be a string"), nil
+			}
 
 			edits = append(edits, tools.TextEdit{
-				StartLine: int(startLine),
-				EndLine:   int(endLine),
-				NewText:   newText,
+				StartLine:        int(startLine),
+				EndLine:          int(endLine),
+				NewText:          newText,
+				OldText:          oldText,
+				ExpectedText:     expectedText,
+				ExpectedFileHash: expectedFileHash,
 			})
 		}
 
+		showDiagnostics := false // default value
+		if showDiagnosticsArg, ok := request.Params.Arguments["showDiagnostics"].(bool); ok {
+			showDiagnostics = showDiagnosticsArg
+		}
+
 		coreLogger.Debug("Executing edit_file for file: %s", filePath)
-		response, err := tools.ApplyTextEdits(s.ctx, s.lspClient, filePath, edits)
+		response, err := tools.ApplyTextEdits(ctx, s.lspClient, filePath, edits, showDiagnostics)
 		if err != nil {
 			coreLogger.Error("Failed to apply edits: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to apply edits: %v", err)), nil
@@ -96,22 +144,36 @@ func (s *mcpServer) registerTools() error {
 	})
 
 	readDefinitionTool := mcp.NewTool("definition",
-		mcp.WithDescription("Read the source code definition of a symbol (function, type, constant, etc.) from the codebase. Returns the complete implementation code where the symbol is defined."),
+		mcp.WithDescription("Read the source code definition of a symbol (function, type, constant, etc.) from the codebase. Returns the complete implementation code where the symbol is defined, and the hash of its file to pass to edit_file as expectedFileHash. Identify the symbol either by name, or by its position in a file for locals, parameters, fields and other symbols that cannot be found by name."),
 		mcp.WithString("symbolName",
-			mcp.Required(),
-			mcp.Description("The name of the symbol whose definition you want to find (e.g. 'mypackage.MyFunction', 'MyType.MyMethod')"),
+			mcp.Description("The name of the symbol whose definition you want to find (e.g. 'mypackage.MyFunction', 'MyType.MyMethod'). If omitted, filePath, line and column are used instead."),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to a file where the symbol is used"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number where the symbol is used (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number where the symbol is used (1-indexed)"),
 		),
 	)
 
 	s.mcpServer.AddTool(readDefinitionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
-		symbolName, ok := request.Params.Arguments["symbolName"].(string)
-		if !ok {
-			return mcp.NewToolResultError("symbolName must be a string"), nil
+		symbolName, filePath, line, column, err := symbolOrPosition(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		coreLogger.Debug("Executing definition for symbol: %s", symbolName)
-		text, err := tools.ReadDefinition(s.ctx, s.lspClient, symbolName)
+		var text string
+		if symbolName != "" {
+			coreLogger.Debug("Executing definition for symbol: %s", symbolName)
+			text, err = tools.ReadDefinition(ctx, s.lspClient, symbolName)
+		} else {
+			coreLogger.Debug("Executing definition for file: %s line: %d column: %d", filePath, line, column)
+			text, err = tools.ReadDefinitionAtPosition(ctx, s.lspClient, filePath, line, column)
+		}
 		if err != nil {
 			coreLogger.Error("Failed to get definition: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to get definition: %v", err)), nil
@@ -120,22 +182,36 @@ func (s *mcpServer) registerTools() error {
 	})
 
 	findReferencesTool := mcp.NewTool("references",
-		mcp.WithDescription("Find all usages and references of a symbol throughout the codebase. Returns a list of all files and locations where the symbol appears."),
+		mcp.WithDescription("Find all usages and references of a symbol throughout the codebase. Returns a list of all files and locations where the symbol appears. Identify the symbol either by name, or by its position in a file for locals, parameters, fields and other symbols that cannot be found by name."),
 		mcp.WithString("symbolName",
-			mcp.Required(),
-			mcp.Description("The name of the symbol to search for (e.g. 'mypackage.MyFunction', 'MyType')"),
+			mcp.Description("The name of the symbol to search for (e.g. 'mypackage.MyFunction', 'MyType'). If omitted, filePath, line and column are used instead."),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to a file where the symbol is used"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number where the symbol is used (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number where the symbol is used (1-indexed)"),
 		),
 	)
 
 	s.mcpServer.AddTool(findReferencesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
-		symbolName, ok := request.Params.Arguments["symbolName"].(string)
-		if !ok {
-			return mcp.NewToolResultError("symbolName must be a string"), nil
+		symbolName, filePath, line, column, err := symbolOrPosition(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		coreLogger.Debug("Executing references for symbol: %s", symbolName)
-		text, err := tools.FindReferences(s.ctx, s.lspClient, symbolName)
+		var text string
+		if symbolName != "" {
+			coreLogger.Debug("Executing references for symbol: %s", symbolName)
+			text, err = tools.FindReferences(ctx, s.lspClient, symbolName)
+		} else {
+			coreLogger.Debug("Executing references for file: %s line: %d column: %d", filePath, line, column)
+			text, err = tools.FindReferencesAtPosition(ctx, s.lspClient, filePath, line, column)
+		}
 		if err != nil {
 			coreLogger.Error("Failed to find references: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to find references: %v", err)), nil
@@ -157,6 +233,10 @@ func (s *mcpServer) registerTools() error {
 			mcp.Description("If true, adds line numbers to the output"),
 			mcp.DefaultBool(true),
 		),
+		mcp.WithBoolean("showFixes",
+			mcp.Description("If true, lists the titles of the quick fixes available for each diagnostic. Apply one with apply_code_action."),
+			mcp.DefaultBool(false),
+		),
 	)
 
 	s.mcpServer.AddTool(getDiagnosticsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -176,8 +256,13 @@ func (s *mcpServer) registerTools() error {
 			showLineNumbers = showLineNumbersArg
 		}
 
+		showFixes := false // default value
+		if showFixesArg, ok := request.Params.Arguments["showFixes"].(bool); ok {
+			showFixes = showFixesArg
+		}
+
 		coreLogger.Debug("Executing diagnostics for file: %s", filePath)
-		text, err := tools.GetDiagnosticsForFile(s.ctx, s.lspClient, filePath, contextLines, showLineNumbers)
+		text, err := tools.GetDiagnosticsForFile(ctx, s.lspClient, filePath, contextLines, showLineNumbers, showFixes)
 		if err != nil {
 			coreLogger.Error("Failed to get diagnostics: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to get diagnostics: %v", err)), nil
@@ -203,7 +288,7 @@ func (s *mcpServer) registerTools() error {
 	// 	}
 	//
 	// 	coreLogger.Debug("Executing get_codelens for file: %s", filePath)
-	// 	text, err := tools.GetCodeLens(s.ctx, s.lspClient, filePath)
+	// 	text, err := tools.GetCodeLens(ctx, s.lspClient, filePath)
 	// 	if err != nil {
 	// 		coreLogger.Error("Failed to get code lens: %v", err)
 	// 		return mcp.NewToolResultError(fmt.Sprintf("failed to get code lens: %v", err)), nil
@@ -242,7 +327,7 @@ func (s *mcpServer) registerTools() error {
 	// 	}
 	//
 	// 	coreLogger.Debug("Executing execute_codelens for file: %s index: %d", filePath, index)
-	// 	text, err := tools.ExecuteCodeLens(s.ctx, s.lspClient, filePath, index)
+	// 	text, err := tools.ExecuteCodeLens(ctx, s.lspClient, filePath, index)
 	// 	if err != nil {
 	// 		coreLogger.Error("Failed to execute code lens: %v", err)
 	// 		return mcp.NewToolResultError(fmt.Sprintf("failed to execute code lens: %v", err)), nil
@@ -294,7 +379,7 @@ func (s *mcpServer) registerTools() error {
 		}
 
 		coreLogger.Debug("Executing hover for file: %s line: %d column: %d", filePath, line, column)
-		text, err := tools.GetHoverInfo(s.ctx, s.lspClient, filePath, line, column)
+		text, err := tools.GetHoverInfo(ctx, s.lspClient, filePath, line, column)
 		if err != nil {
 			coreLogger.Error("Failed to get hover information: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to get hover information: %v", err)), nil
@@ -303,7 +388,7 @@ func (s *mcpServer) registerTools() error {
 	})
 
 	renameSymbolTool := mcp.NewTool("rename_symbol",
-		mcp.WithDescription("Rename a symbol (variable, function, class, etc.) at the specified position and update all references throughout the codebase."),
+		mcp.WithDescription("Rename a symbol (variable, function, class, etc.) at the specified position and update all references throughout the codebase. Use dryRun to check what would be renamed and preview the changes as a diff without writing any files."),
 		mcp.WithString("filePath",
 			mcp.Required(),
 			mcp.Description("The path to the file containing the symbol to rename"),
@@ -320,6 +405,14 @@ func (s *mcpServer) registerTools() error {
 			mcp.Required(),
 			mcp.Description("The new name for the symbol"),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Validate the position and return a diff of the changes without writing them"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("showDiagnostics",
+			mcp.Description("Wait for the language server to check the changed files and list the diagnostics the rename introduced or resolved"),
+			mcp.DefaultBool(false),
+		),
 	)
 
 	s.mcpServer.AddTool(renameSymbolTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -354,8 +447,18 @@ func (s *mcpServer) registerTools() error {
 			return mcp.NewToolResultError("column must be a number"), nil
 		}
 
-		coreLogger.Debug("Executing rename_symbol for file: %s line: %d column: %d newName: %s", filePath, line, column, newName)
-		text, err := tools.RenameSymbol(s.ctx, s.lspClient, filePath, line, column, newName)
+		dryRun := false // default value
+		if dryRunArg, ok := request.Params.Arguments["dryRun"].(bool); ok {
+			dryRun = dryRunArg
+		}
+
+		showDiagnostics := false // default value
+		if showDiagnosticsArg, ok := request.Params.Arguments["showDiagnostics"].(bool); ok {
+			showDiagnostics = showDiagnosticsArg
+		}
+
+		coreLogger.Debug("Executing rename_symbol for file: %s line: %d column: %d newName: %s dryRun: %v", filePath, line, column, newName, dryRun)
+		text, err := tools.RenameSymbol(ctx, s.lspClient, filePath, line, column, newName, dryRun, showDiagnostics)
 		if err != nil {
 			coreLogger.Error("Failed to rename symbol: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to rename symbol: %v", err)), nil
@@ -363,6 +466,960 @@ func (s *mcpServer) registerTools() error {
 		return mcp.NewToolResultText(text), nil
 	})
 
-	coreLogger.Info("Successfully registered all MCP tools")
-	return nil
+	incomingCallsTool := mcp.NewTool("incoming_calls",
+		mcp.WithDescription("Find the functions and methods that call a symbol, as a tree of callers up to the given depth. Each caller includes its location and the lines where the calls are made. Identify the symbol either by name or by its position in a file."),
+		mcp.WithString("symbolName",
+			mcp.Description("The name of the symbol (e.g. 'mypackage.MyFunction', 'MyType.MyMethod'). If omitted, filePath, line and column are used instead."),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to the file containing the symbol"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number where the symbol is located (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number where the symbol is located (1-indexed)"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("How many levels of calls to follow"),
+			mcp.DefaultNumber(3),
+		),
+	)
+
+	s.mcpServer.AddTool(incomingCallsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		symbolName, filePath, line, column, err := symbolOrPosition(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		depth := 3 // default value
+		if depthArg, ok := numberArg(request.Params.Arguments, "depth"); ok {
+			depth = depthArg
+		}
+
+		coreLogger.Debug("Executing incoming_calls for symbol: %s file: %s line: %d column: %d depth: %d", symbolName, filePath, line, column, depth)
+		text, err := tools.GetIncomingCalls(ctx, s.lspClient, symbolName, filePath, line, column, depth)
+		if err != nil {
+			coreLogger.Error("Failed to get incoming calls: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get incoming calls: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	outgoingCallsTool := mcp.NewTool("outgoing_calls",
+		mcp.WithDescription("Find the functions and methods called by a symbol, as a tree of callees up to the given depth. Each callee includes its location and the lines where the calls are made. Identify the symbol either by name or by its position in a file."),
+		mcp.WithString("symbolName",
+			mcp.Description("The name of the symbol (e.g. 'mypackage.MyFunction', 'MyType.MyMethod'). If omitted, filePath, line and column are used instead."),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to the file containing the symbol"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number where the symbol is located (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number where the symbol is located (1-indexed)"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("How many levels of calls to follow"),
+			mcp.DefaultNumber(3),
+		),
+	)
+
+	s.mcpServer.AddTool(outgoingCallsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		symbolName, filePath, line, column, err := symbolOrPosition(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		depth := 3 // default value
+		if depthArg, ok := numberArg(request.Params.Arguments, "depth"); ok {
+			depth = depthArg
+		}
+
+		coreLogger.Debug("Executing outgoing_calls for symbol: %s file: %s line: %d column: %d depth: %d", symbolName, filePath, line, column, depth)
+		text, err := tools.GetOutgoingCalls(ctx, s.lspClient, symbolName, filePath, line, column, depth)
+		if err != nil {
+			coreLogger.Error("Failed to get outgoing calls: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get outgoing calls: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	typeHierarchyTool := mcp.NewTool("type_hierarchy",
+		mcp.WithDescription("Find the supertypes (types it extends or implements) and subtypes (types that extend or implement it) of a type, as an indented tree up to the given depth. Identify the type either by name or by its position in a file."),
+		mcp.WithString("symbolName",
+			mcp.Description("The name of the type (e.g. 'mypackage.MyInterface', 'MyClass'). If omitted, filePath, line and column are used instead."),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to the file containing the type"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number where the type is located (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number where the type is located (1-indexed)"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Which part of the hierarchy to show: 'up' for supertypes, 'down' for subtypes or 'both'"),
+			mcp.Enum("up", "down", "both"),
+			mcp.DefaultString("both"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("How many levels of the hierarchy to follow"),
+			mcp.DefaultNumber(3),
+		),
+	)
+
+	s.mcpServer.AddTool(typeHierarchyTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		symbolName, filePath, line, column, err := symbolOrPosition(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		direction := "both" // default value
+		if directionArg, ok := request.Params.Arguments["direction"].(string); ok {
+			direction = directionArg
+		}
+
+		depth := 3 // default value
+		if depthArg, ok := numberArg(request.Params.Arguments, "depth"); ok {
+			depth = depthArg
+		}
+
+		coreLogger.Debug("Executing type_hierarchy for symbol: %s file: %s line: %d column: %d direction: %s depth: %d", symbolName, filePath, line, column, direction, depth)
+		text, err := tools.GetTypeHierarchy(ctx, s.lspClient, symbolName, filePath, line, column, direction, depth)
+		if err != nil {
+			coreLogger.Error("Failed to get type hierarchy: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get type hierarchy: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	implementationTool := mcp.NewTool("implementation",
+		mcp.WithDescription("Find the implementations of the symbol at the specified position, such as the concrete types implementing an interface or the methods implementing an interface method. Returns the complete source code of each implementation."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file containing the symbol"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number where the symbol is located (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number where the symbol is located (1-indexed)"),
+		),
+	)
+
+	s.mcpServer.AddTool(implementationTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		line, column, err := positionArgs(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing implementation for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.FindImplementations(ctx, s.lspClient, filePath, line, column)
+		if err != nil {
+			coreLogger.Error("Failed to get implementations: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get implementations: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	typeDefinitionTool := mcp.NewTool("type_definition",
+		mcp.WithDescription("Read the source code definition of the type of the symbol at the specified position, such as the declared type of a variable, parameter or field. Returns the complete type declaration."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file containing the symbol"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number where the symbol is located (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number where the symbol is located (1-indexed)"),
+		),
+	)
+
+	s.mcpServer.AddTool(typeDefinitionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		line, column, err := positionArgs(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing type_definition for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.ReadTypeDefinition(ctx, s.lspClient, filePath, line, column)
+		if err != nil {
+			coreLogger.Error("Failed to get type definition: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get type definition: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	documentSymbolsTool := mcp.NewTool("document_symbols",
+		mcp.WithDescription("Get an outline of the symbols (types, functions, methods, fields, etc.) in a file with their kind, signature and line range. Much cheaper than reading the whole file to find out what it contains."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to get the outline of"),
+		),
+		mcp.WithArray("kinds",
+			mcp.Description("Only include symbols of these kinds (e.g. 'Function', 'Method', 'Class', 'Struct', 'Interface', 'Field')"),
+			mcp.Items(map[string]any{
+				"type": "string",
+			}),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum nesting depth to include, e.g. 1 for top level symbols only. 0 includes all levels."),
+			mcp.DefaultNumber(0),
+		),
+	)
+
+	s.mcpServer.AddTool(documentSymbolsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		kinds, ok := stringArrayArg(request.Params.Arguments, "kinds")
+		if !ok {
+			return mcp.NewToolResultError("kinds must be an array of strings"), nil
+		}
+
+		maxDepth := 0 // default value
+		if maxDepthArg, ok := numberArg(request.Params.Arguments, "maxDepth"); ok {
+			maxDepth = maxDepthArg
+		}
+
+		coreLogger.Debug("Executing document_symbols for file: %s kinds: %v maxDepth: %d", filePath, kinds, maxDepth)
+		text, err := tools.GetDocumentSymbols(ctx, s.lspClient, filePath, kinds, maxDepth)
+		if err != nil {
+			coreLogger.Error("Failed to get document symbols: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get document symbols: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	workspaceSymbolsTool := mcp.NewTool("workspace_symbols",
+		mcp.WithDescription("Search the whole workspace for symbols whose name matches a query, ranked by the language server's fuzzy matching. Returns each symbol's kind, container and location. Use this when you only know part of a name or are exploring an unfamiliar codebase."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The text to search symbol names for. Matching is fuzzy and depends on the language server."),
+		),
+		mcp.WithArray("kinds",
+			mcp.Description("Only include symbols of these kinds (e.g. 'Function', 'Method', 'Class', 'Struct', 'Interface')"),
+			mcp.Items(map[string]any{
+				"type": "string",
+			}),
+		),
+		mcp.WithString("pathGlob",
+			mcp.Description("Only include symbols in files matching this glob, e.g. 'internal/**/*.go'. '**' matches any number of directories."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of results to return"),
+			mcp.DefaultNumber(50),
+		),
+	)
+
+	s.mcpServer.AddTool(workspaceSymbolsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		query, ok := request.Params.Arguments["query"].(string)
+		if !ok {
+			return mcp.NewToolResultError("query must be a string"), nil
+		}
+
+		kinds, ok := stringArrayArg(request.Params.Arguments, "kinds")
+		if !ok {
+			return mcp.NewToolResultError("kinds must be an array of strings"), nil
+		}
+
+		pathGlob := "" // default value
+		if pathGlobArg, ok := request.Params.Arguments["pathGlob"].(string); ok {
+			pathGlob = pathGlobArg
+		}
+
+		limit := 50 // default value
+		if limitArg, ok := numberArg(request.Params.Arguments, "limit"); ok {
+			limit = limitArg
+		}
+
+		coreLogger.Debug("Executing workspace_symbols for query: %s kinds: %v pathGlob: %s limit: %d", query, kinds, pathGlob, limit)
+		text, err := tools.SearchWorkspaceSymbols(ctx, s.lspClient, query, kinds, pathGlob, limit)
+		if err != nil {
+			coreLogger.Error("Failed to search workspace symbols: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to search workspace symbols: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	codeActionsTool := mcp.NewTool("code_actions",
+		mcp.WithDescription("List the quick fixes, refactorings and source actions the language server offers for a range of lines or for a specific diagnostic, such as adding a missing import, filling a struct or extracting a function. Apply one with apply_code_action."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to get code actions for"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Required(),
+			mcp.Description("The first line of the range, 1 indexed"),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("The last line of the range, 1 indexed and inclusive. Defaults to startLine."),
+		),
+		mcp.WithString("diagnostic",
+			mcp.Description("Only get fixes for the diagnostic in the range whose message contains this text"),
+		),
+		mcp.WithArray("kinds",
+			mcp.Description("Only include actions of these kinds (e.g. 'quickfix', 'refactor', 'refactor.extract', 'source.organizeImports')"),
+			mcp.Items(map[string]any{
+				"type": "string",
+			}),
+		),
+	)
+
+	s.mcpServer.AddTool(codeActionsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		actionRange, err := codeActionRangeArgs(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing code_actions for file: %s lines: %d-%d", actionRange.FilePath, actionRange.StartLine, actionRange.EndLine)
+		text, err := tools.GetCodeActions(ctx, s.lspClient, actionRange)
+		if err != nil {
+			coreLogger.Error("Failed to get code actions: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get code actions: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	applyCodeActionTool := mcp.NewTool("apply_code_action",
+		mcp.WithDescription("Apply one of the code actions listed by code_actions. Pass the same range, diagnostic and kinds that were used to list the actions, and the index of the action to apply."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file the code actions were listed for"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Required(),
+			mcp.Description("The first line of the range, 1 indexed"),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("The last line of the range, 1 indexed and inclusive. Defaults to startLine."),
+		),
+		mcp.WithString("diagnostic",
+			mcp.Description("The diagnostic text the code actions were listed for, if any"),
+		),
+		mcp.WithArray("kinds",
+			mcp.Description("The kinds the code actions were filtered by, if any"),
+			mcp.Items(map[string]any{
+				"type": "string",
+			}),
+		),
+		mcp.WithNumber("index",
+			mcp.Required(),
+			mcp.Description("The index of the code action to apply (from code_actions output), 1 indexed"),
+		),
+	)
+
+	s.mcpServer.AddTool(applyCodeActionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		actionRange, err := codeActionRangeArgs(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		index, ok := numberArg(request.Params.Arguments, "index")
+		if !ok {
+			return mcp.NewToolResultError("index must be a number"), nil
+		}
+
+		coreLogger.Debug("Executing apply_code_action for file: %s lines: %d-%d index: %d", actionRange.FilePath, actionRange.StartLine, actionRange.EndLine, index)
+		text, err := tools.ApplyCodeAction(ctx, s.lspClient, actionRange, index)
+		if err != nil {
+			coreLogger.Error("Failed to apply code action: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to apply code action: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	formatFileTool := mcp.NewTool("format_file",
+		mcp.WithDescription("Format a file, or a range of lines in it, with the language server's formatter and save the result. Use this after editing to fix indentation and style."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to format"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Description("The first line to format, 1 indexed. If omitted, the whole file is formatted. Not all language servers support formatting a range."),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("The last line to format, 1 indexed and inclusive. Defaults to startLine."),
+		),
+		mcp.WithNumber("tabSize",
+			mcp.Description("Size of a tab in spaces"),
+			mcp.DefaultNumber(4),
+		),
+		mcp.WithBoolean("insertSpaces",
+			mcp.Description("Prefer spaces over tabs"),
+			mcp.DefaultBool(true),
+		),
+	)
+
+	s.mcpServer.AddTool(formatFileTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		startLine := 0 // default value
+		if startLineArg, ok := numberArg(request.Params.Arguments, "startLine"); ok {
+			startLine = startLineArg
+		}
+
+		endLine := startLine // default value
+		if endLineArg, ok := numberArg(request.Params.Arguments, "endLine"); ok {
+			endLine = endLineArg
+		}
+
+		tabSize := 4 // default value
+		if tabSizeArg, ok := numberArg(request.Params.Arguments, "tabSize"); ok {
+			tabSize = tabSizeArg
+		}
+
+		insertSpaces := true // default value
+		if insertSpacesArg, ok := request.Params.Arguments["insertSpaces"].(bool); ok {
+			insertSpaces = insertSpacesArg
+		}
+
+		coreLogger.Debug("Executing format_file for file: %s lines: %d-%d", filePath, startLine, endLine)
+		text, err := tools.FormatFile(ctx, s.lspClient, filePath, startLine, endLine, tabSize, insertSpaces)
+		if err != nil {
+			coreLogger.Error("Failed to format file: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format file: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	organizeImportsTool := mcp.NewTool("organize_imports",
+		mcp.WithDescription("Organize the imports of a file using the language server: add missing imports, remove unused ones and sort them. Run this after edits that add or remove code using other packages."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to organize the imports of"),
+		),
+	)
+
+	s.mcpServer.AddTool(organizeImportsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		coreLogger.Debug("Executing organize_imports for file: %s", filePath)
+		text, err := tools.OrganizeImports(ctx, s.lspClient, filePath)
+		if err != nil {
+			coreLogger.Error("Failed to organize imports: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to organize imports: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	signatureHelpTool := mcp.NewTool("signature_help",
+		mcp.WithDescription("Get the signature of the function being called at a position: its parameter names and types, which parameter the cursor is on, and documentation. Use this to check argument order before writing a call."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file containing the call"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number of the position inside the call's parentheses (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number of the position inside the call's parentheses (1-indexed)"),
+		),
+		mcp.WithString("text",
+			mcp.Description("Text to insert at the position before requesting signature help, without saving the file, e.g. 'strings.Replace(' to see the parameters of a call you are about to write"),
+		),
+	)
+
+	s.mcpServer.AddTool(signatureHelpTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		line, column, err := positionArgs(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		text, _ := request.Params.Arguments["text"].(string)
+
+		coreLogger.Debug("Executing signature_help for file: %s line: %d column: %d", filePath, line, column)
+		result, err := tools.GetSignatureHelp(ctx, s.lspClient, filePath, line, column, text)
+		if err != nil {
+			coreLogger.Error("Failed to get signature help: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get signature help: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	})
+
+	completionTool := mcp.NewTool("completion",
+		mcp.WithDescription("Get the completions the language server suggests at a position, with their kind, type or signature and documentation. Use this to find the exact names of methods, fields and functions instead of guessing them."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to get completions in"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number where completion is requested (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number where completion is requested (1-indexed)"),
+		),
+		mcp.WithString("text",
+			mcp.Description("Text to insert at the position before requesting completions, without saving the file, e.g. 'client.' to list the members of client"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of completion items to return"),
+			mcp.DefaultNumber(20),
+		),
+	)
+
+	s.mcpServer.AddTool(completionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		line, column, err := positionArgs(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		text, _ := request.Params.Arguments["text"].(string)
+
+		limit := 20 // default value
+		if limitArg, ok := numberArg(request.Params.Arguments, "limit"); ok {
+			limit = limitArg
+		}
+
+		coreLogger.Debug("Executing completion for file: %s line: %d column: %d limit: %d", filePath, line, column, limit)
+		result, err := tools.GetCompletions(ctx, s.lspClient, filePath, line, column, text, limit)
+		if err != nil {
+			coreLogger.Error("Failed to get completions: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get completions: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	})
+
+	inlayHintsTool := mcp.NewTool("inlay_hints",
+		mcp.WithDescription("Show the lines of a file with the language server's inlay hints inlined as comments, such as inferred variable types (x /*: map[string]int */ := ...) and parameter names at call sites. Use this instead of many hover calls to understand code with heavy type inference."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to show inlay hints for"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Description("The first line to show, 1 indexed. If omitted, the whole file is shown."),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("The last line to show, 1 indexed and inclusive. Defaults to startLine."),
+		),
+	)
+
+	s.mcpServer.AddTool(inlayHintsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		startLine := 0 // default value
+		if startLineArg, ok := numberArg(request.Params.Arguments, "startLine"); ok {
+			startLine = startLineArg
+		}
+
+		endLine := startLine // default value
+		if endLineArg, ok := numberArg(request.Params.Arguments, "endLine"); ok {
+			endLine = endLineArg
+		}
+
+		coreLogger.Debug("Executing inlay_hints for file: %s lines: %d-%d", filePath, startLine, endLine)
+		text, err := tools.GetInlayHints(ctx, s.lspClient, filePath, startLine, endLine)
+		if err != nil {
+			coreLogger.Error("Failed to get inlay hints: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get inlay hints: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	highlightsTool := mcp.NewTool("highlights",
+		mcp.WithDescription("List every occurrence in a file of the symbol at a position, grouped into writes and reads, with surrounding lines. Use this before changing a variable to find every place that assigns to it."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file containing the symbol"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number of the symbol (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number of the symbol (1-indexed)"),
+		),
+		mcp.WithNumber("contextLines",
+			mcp.Description("Lines to include around each occurrence"),
+			mcp.DefaultNumber(1),
+		),
+	)
+
+	s.mcpServer.AddTool(highlightsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		line, column, err := positionArgs(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		contextLines := 1 // default value
+		if contextLinesArg, ok := numberArg(request.Params.Arguments, "contextLines"); ok {
+			contextLines = contextLinesArg
+		}
+
+		coreLogger.Debug("Executing highlights for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.GetHighlights(ctx, s.lspClient, filePath, line, column, contextLines)
+		if err != nil {
+			coreLogger.Error("Failed to get highlights: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get highlights: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	listChangesTool := mcp.NewTool("list_changes",
+		mcp.WithDescription("List the most recent changes made to files through this server, such as edits, renames, code actions and formatting, with the files each one touched. Each change has an ID that can be passed to undo_change."),
+		mcp.WithNumber("limit",
+			mcp.Description("The maximum number of changes to list, most recent first"),
+			mcp.DefaultNumber(20),
+		),
+	)
+
+	s.mcpServer.AddTool(listChangesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		limit := 20 // default value
+		if limitArg, ok := numberArg(request.Params.Arguments, "limit"); ok {
+			limit = limitArg
+		}
+
+		coreLogger.Debug("Executing list_changes with limit: %d", limit)
+		text, err := tools.ListChanges(limit)
+		if err != nil {
+			coreLogger.Error("Failed to list changes: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list changes: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	undoChangeTool := mcp.NewTool("undo_change",
+		mcp.WithDescription("Undo a change listed by list_changes, restoring every file it touched to its previous content in one step. Fails without changing anything if any of the files were modified since, unless force is set."),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("The ID of the change to undo"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Undo even if the files were modified since the change, discarding those modifications"),
+			mcp.DefaultBool(false),
+		),
+	)
+
+	s.mcpServer.AddTool(undoChangeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		id, ok := numberArg(request.Params.Arguments, "id")
+		if !ok {
+			return mcp.NewToolResultError("id must be a number"), nil
+		}
+
+		force := false // default value
+		if forceArg, ok := request.Params.Arguments["force"].(bool); ok {
+			force = forceArg
+		}
+
+		coreLogger.Debug("Executing undo_change for id: %d force: %v", id, force)
+		text, err := tools.UndoChange(id, force)
+		if err != nil {
+			coreLogger.Error("Failed to undo change: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to undo change: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	replaceSymbolTool := mcp.NewTool("replace_symbol",
+		mcp.WithDescription("Replace the complete source of a function, method, type or other symbol, as returned by the definition tool, with new code. The symbol is found by name, so no line numbers are needed."),
+		mcp.WithString("symbolName",
+			mcp.Required(),
+			mcp.Description("The name of the symbol to replace (e.g. 'MyFunction', 'MyType.MyMethod')"),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to the file containing the symbol, to choose between symbols with the same name"),
+		),
+		mcp.WithString("newText",
+			mcp.Required(),
+			mcp.Description("The new source of the symbol, including its signature. If it starts with a comment, it also replaces the symbol's doc comment, otherwise the existing doc comment is kept."),
+		),
+	)
+
+	s.mcpServer.AddTool(replaceSymbolTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		symbolName, ok := request.Params.Arguments["symbolName"].(string)
+		if !ok {
+			return mcp.NewToolResultError("symbolName must be a string"), nil
+		}
+
+		filePath, _ := request.Params.Arguments["filePath"].(string)
+
+		newText, ok := request.Params.Arguments["newText"].(string)
+		if !ok {
+			return mcp.NewToolResultError("newText must be a string"), nil
+		}
+
+		coreLogger.Debug("Executing replace_symbol for symbol: %s file: %s", symbolName, filePath)
+		text, err := tools.ReplaceSymbol(ctx, s.lspClient, symbolName, filePath, newText)
+		if err != nil {
+			coreLogger.Error("Failed to replace symbol: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to replace symbol: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	insertAfterSymbolTool := mcp.NewTool("insert_after_symbol",
+		mcp.WithDescription("Insert new code after a function, method, type or other symbol, or before it and its doc comment, separated from it by a blank line. The symbol is found by name, so no line numbers are needed."),
+		mcp.WithString("symbolName",
+			mcp.Required(),
+			mcp.Description("The name of the symbol to insert next to (e.g. 'MyFunction', 'MyType.MyMethod')"),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to the file containing the symbol, to choose between symbols with the same name"),
+		),
+		mcp.WithString("newText",
+			mcp.Required(),
+			mcp.Description("The code to insert"),
+		),
+		mcp.WithBoolean("before",
+			mcp.Description("Insert before the symbol instead of after it"),
+			mcp.DefaultBool(false),
+		),
+	)
+
+	s.mcpServer.AddTool(insertAfterSymbolTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		symbolName, ok := request.Params.Arguments["symbolName"].(string)
+		if !ok {
+			return mcp.NewToolResultError("symbolName must be a string"), nil
+		}
+
+		filePath, _ := request.Params.Arguments["filePath"].(string)
+
+		newText, ok := request.Params.Arguments["newText"].(string)
+		if !ok {
+			return mcp.NewToolResultError("newText must be a string"), nil
+		}
+
+		before := false // default value
+		if beforeArg, ok := request.Params.Arguments["before"].(bool); ok {
+			before = beforeArg
+		}
+
+		coreLogger.Debug("Executing insert_after_symbol for symbol: %s file: %s before: %v", symbolName, filePath, before)
+		text, err := tools.InsertAfterSymbol(ctx, s.lspClient, symbolName, filePath, newText, before)
+		if err != nil {
+			coreLogger.Error("Failed to insert code: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to insert code: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	workspaceDiagnosticsTool := mcp.NewTool("workspace_diagnostics",
+		mcp.WithDescription("List the diagnostics of the whole workspace, grouped per file with counts by severity. Use this to check that the project still compiles cleanly after a change. Covers every file the language server has checked, which depends on the server."),
+		mcp.WithString("severity",
+			mcp.Description("The least severe diagnostics to include: 'error', 'warning', 'info' or 'hint'. For example 'warning' lists errors and warnings."),
+			mcp.Enum("error", "warning", "info", "hint"),
+		),
+		mcp.WithString("source",
+			mcp.Description("Only include diagnostics from this source, e.g. 'compiler' or 'eslint'"),
+		),
+		mcp.WithString("code",
+			mcp.Description("Only include diagnostics with this code"),
+		),
+		mcp.WithString("pathGlob",
+			mcp.Description("Only include files matching this glob, e.g. 'internal/**/*.go'. '**' matches any number of directories."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of diagnostics to list. The counts include all of them."),
+			mcp.DefaultNumber(100),
+		),
+	)
+
+	s.mcpServer.AddTool(workspaceDiagnosticsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		var filter tools.WorkspaceDiagnosticsFilter
+		if severityArg, ok := request.Params.Arguments["severity"].(string); ok {
+			filter.MinSeverity = severityArg
+		}
+		if sourceArg, ok := request.Params.Arguments["source"].(string); ok {
+			filter.Source = sourceArg
+		}
+		if codeArg, ok := request.Params.Arguments["code"].(string); ok {
+			filter.Code = codeArg
+		}
+		if pathGlobArg, ok := request.Params.Arguments["pathGlob"].(string); ok {
+			filter.PathGlob = pathGlobArg
+		}
+
+		limit := 100 // default value
+		if limitArg, ok := numberArg(request.Params.Arguments, "limit"); ok {
+			limit = limitArg
+		}
+
+		coreLogger.Debug("Executing workspace_diagnostics with filter: %+v limit: %d", filter, limit)
+		text, err := tools.GetWorkspaceDiagnostics(ctx, s.lspClient, filter, limit)
+		if err != nil {
+			coreLogger.Error("Failed to get workspace diagnostics: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get workspace diagnostics: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	coreLogger.Info("Successfully registered all MCP tools")
+	return nil
+}
+
+// numberArg extracts a numeric argument, handling both float64 and int due to JSON parsing
+func numberArg(args map[string]any, name string) (int, bool) {
+	switch v := args[name].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// positionArgs extracts the 1-indexed line and column of a position from the arguments
+func positionArgs(args map[string]any) (line, column int, err error) {
+	line, ok := numberArg(args, "line")
+	if !ok {
+		return 0, 0, fmt.Errorf("line must be a number")
+	}
+
+	column, ok = numberArg(args, "column")
+	if !ok {
+		return 0, 0, fmt.Errorf("column must be a number")
+	}
+
+	// Lower values would wrap around when converted to a 0-indexed protocol position
+	if line < 1 {
+		return 0, 0, fmt.Errorf("line must be at least 1")
+	}
+	if column < 1 {
+		return 0, 0, fmt.Errorf("column must be at least 1")
+	}
+
+	return line, column, nil
+}
+
+// stringArrayArg extracts an optional array of strings from the arguments
+func stringArrayArg(args map[string]any, name string) ([]string, bool) {
+	value, exists := args[name]
+	if !exists || value == nil {
+		return nil, true
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, str)
+	}
+	return result, true
+}
+
+// symbolOrPosition extracts either a symbol name or a file path, line and column from the arguments
+func symbolOrPosition(args map[string]any) (symbolName, filePath string, line, column int, err error) {
+	if symbolName, ok := args["symbolName"].(string); ok && symbolName != "" {
+		return symbolName, "", 0, 0, nil
+	}
+
+	filePath, ok := args["filePath"].(string)
+	if !ok || filePath == "" {
+		return "", "", 0, 0, fmt.Errorf("either symbolName or filePath, line and column are required")
+	}
+
+	line, column, err = positionArgs(args)
+	if err != nil {
+		return "", "", 0, 0, err
+	}
+
+	return "", filePath, line, column, nil
+}
+
+// codeActionRangeArgs extracts the range, diagnostic and kinds shared by the code action tools
+func codeActionRangeArgs(args map[string]any) (tools.CodeActionRange, error) {
+	filePath, ok := args["filePath"].(string)
+	if !ok {
+		return tools.CodeActionRange{}, fmt.Errorf("filePath must be a string")
+	}
+
+	startLine, ok := numberArg(args, "startLine")
+	if !ok {
+		return tools.CodeActionRange{}, fmt.Errorf("startLine must be a number")
+	}
+
+	endLine := startLine // default value
+	if endLineArg, ok := numberArg(args, "endLine"); ok {
+		endLine = endLineArg
+	}
+
+	diagnostic, _ := args["diagnostic"].(string)
+
+	kinds, ok := stringArrayArg(args, "kinds")
+	if !ok {
+		return tools.CodeActionRange{}, fmt.Errorf("kinds must be an array of strings")
+	}
+
+	return tools.CodeActionRange{
+		FilePath:   filePath,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		Diagnostic: diagnostic,
+		Kinds:      kinds,
+	}, nil
 }